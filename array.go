@@ -1,17 +1,32 @@
 package god
 
 import (
+	"cmp"
+	"encoding/json"
 	"fmt"
 	"reflect"
+	"time"
 )
 
 type ArraySchema struct {
 	BaseSchema
-	element   Schema
-	minLength *int
-	maxLength *int
-	length    *int
-	nonempty  bool
+	element              Schema
+	minLength            *int
+	maxLength            *int
+	length               *int
+	nonempty             bool
+	maxErrors            *int
+	firstErrorPerElement bool
+	unique               bool
+	uniqueBy             func(interface{}) interface{}
+	includes             []interface{}
+	pairRefinements      []pairRefinement
+}
+
+type pairRefinement struct {
+	check   func(prev, curr interface{}) bool
+	message string
+	code    string
 }
 
 func Array(element Schema) *ArraySchema {
@@ -41,6 +56,66 @@ func (s *ArraySchema) Nonempty() *ArraySchema {
 	return s
 }
 
+func (s *ArraySchema) MaxErrors(count int) *ArraySchema {
+	s.maxErrors = &count
+	return s
+}
+
+func (s *ArraySchema) FirstErrorPerElement() *ArraySchema {
+	s.firstErrorPerElement = true
+	return s
+}
+
+func (s *ArraySchema) Unique() *ArraySchema {
+	s.unique = true
+	return s
+}
+
+func (s *ArraySchema) UniqueBy(keyFn func(interface{}) interface{}) *ArraySchema {
+	s.unique = true
+	s.uniqueBy = keyFn
+	return s
+}
+
+func (s *ArraySchema) Includes(value interface{}) *ArraySchema {
+	s.includes = append(s.includes, value)
+	return s
+}
+
+func (s *ArraySchema) IncludesAll(values ...interface{}) *ArraySchema {
+	s.includes = append(s.includes, values...)
+	return s
+}
+
+func (s *ArraySchema) Sorted(ascending bool) *ArraySchema {
+	order := "ascending"
+	if !ascending {
+		order = "descending"
+	}
+	s.pairRefinements = append(s.pairRefinements, pairRefinement{
+		check: func(prev, curr interface{}) bool {
+			cmp, ok := compareElements(prev, curr)
+			if !ok {
+				return false
+			}
+			if ascending {
+				return cmp <= 0
+			}
+			return cmp >= 0
+		},
+		message: fmt.Sprintf("array must be sorted in %s order", order),
+		code:    "not_sorted",
+	})
+	return s
+}
+
+// RefineElements checks every adjacent pair of validated elements and
+// reports the first pair for which fn returns false.
+func (s *ArraySchema) RefineElements(fn func(prev, curr interface{}) bool, message string) *ArraySchema {
+	s.pairRefinements = append(s.pairRefinements, pairRefinement{check: fn, message: message, code: "custom"})
+	return s
+}
+
 func (s *ArraySchema) Optional() Schema {
 	s.BaseSchema.setOptional()
 	return s
@@ -56,21 +131,228 @@ func (s *ArraySchema) Default(value interface{}) Schema {
 	return s
 }
 
+func (s *ArraySchema) DefaultFunc(fn func() interface{}) Schema {
+	s.BaseSchema.setDefaultFunc(fn)
+	return s
+}
+
+func (s *ArraySchema) Readonly() *ArraySchema {
+	s.BaseSchema.setReadonly()
+	return s
+}
+
+func (s *ArraySchema) WithMessage(code, message string) *ArraySchema {
+	s.BaseSchema.setMessage(code, message)
+	return s
+}
+
 func (s *ArraySchema) Validate(value interface{}) ValidationResult {
+	return s.validate(value, newContext())
+}
+
+func (s *ArraySchema) validate(value interface{}, ctx *validationContext) ValidationResult {
 	processedValue, shouldReturn, result := s.handleNil(value)
 	if shouldReturn {
 		return result
 	}
 
+	if result, ok := ctx.enter(); !ok {
+		return result
+	}
+	defer ctx.leave()
+
 	v := reflect.ValueOf(processedValue)
 	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
 		return ValidationResult{
 			Valid:  false,
-			Errors: []ValidationError{{Message: "expected array", Code: "invalid_type", Value: value}},
+			Errors: s.applyMessages([]ValidationError{{Message: "expected array", Code: "invalid_type", Value: value, Params: map[string]interface{}{"expected": "array"}}}),
 		}
 	}
 
 	length := v.Len()
+	errors, errorSlot := ctx.errorBuffer()
+	errors = append(errors, s.applyMessages(s.lengthErrors(length, value))...)
+
+	var warnings []ValidationError
+	elementsValid := true
+	validatedArray := ctx.sliceBuffer(length)
+	for i := 0; i < length; i++ {
+		if s.maxErrors != nil && len(errors) >= *s.maxErrors {
+			elementsValid = false
+			break
+		}
+		if ctx.abortEarly && len(errors) > 0 {
+			elementsValid = false
+			break
+		}
+		elementValue := v.Index(i).Interface()
+		result := validateChild(s.element, elementValue, ctx)
+		for _, warning := range result.Warnings {
+			warning = warning.withPrefix(indexSegment(i))
+			warnings = append(warnings, warning)
+		}
+		if !result.Valid {
+			elementsValid = false
+			elementErrors := result.Errors
+			if s.firstErrorPerElement && len(elementErrors) > 1 {
+				elementErrors = elementErrors[:1]
+			}
+			for _, err := range elementErrors {
+				if s.maxErrors != nil && len(errors) >= *s.maxErrors {
+					break
+				}
+				err = err.withPrefix(indexSegment(i))
+				errors = append(errors, err)
+			}
+		} else {
+			validatedArray[i] = result.Value
+		}
+	}
+
+	if elementsValid && s.unique {
+		if first, duplicate, found := findDuplicate(validatedArray, s.uniqueBy); found {
+			errors = append(errors, s.applyMessages([]ValidationError{{
+				Field:   fmt.Sprintf("[%d]", duplicate),
+				Path:    []PathSegment{indexSegment(duplicate)},
+				Message: fmt.Sprintf("duplicate element at index %d (first seen at index %d)", duplicate, first),
+				Code:    "not_unique",
+				Value:   validatedArray[duplicate],
+			}})...)
+		}
+	}
+
+	if elementsValid {
+		for _, refinement := range s.pairRefinements {
+			for i := 1; i < len(validatedArray); i++ {
+				if !refinement.check(validatedArray[i-1], validatedArray[i]) {
+					errors = append(errors, s.applyMessages([]ValidationError{{
+						Field:   fmt.Sprintf("[%d]", i),
+						Path:    []PathSegment{indexSegment(i)},
+						Message: refinement.message,
+						Code:    refinement.code,
+						Value:   validatedArray[i],
+					}})...)
+					break
+				}
+			}
+		}
+
+		for _, required := range s.includes {
+			if !containsElement(validatedArray, required) {
+				errors = append(errors, s.applyMessages([]ValidationError{{
+					Message: fmt.Sprintf("array must include %v", required),
+					Code:    "missing_element",
+					Value:   value,
+					Params:  map[string]interface{}{"element": required},
+				}})...)
+			}
+		}
+	}
+
+	if len(errors) > 0 {
+		return ValidationResult{Valid: false, Errors: ctx.keepErrors(errorSlot, errors), Warnings: warnings}
+	}
+
+	return ValidationResult{Valid: true, Value: validatedArray, Warnings: warnings}
+}
+
+// StreamError reports the array-level constraints that failed once
+// ValidateStream reached the end of the array, such as Min, Max or Includes.
+type StreamError struct {
+	Errors []ValidationError
+}
+
+func (e *StreamError) Error() string {
+	return ValidationResult{Errors: e.Errors}.Error().Error()
+}
+
+// ValidateStream reads a JSON array from dec one element at a time, so only
+// the current element is held in memory. Each element is validated and passed
+// to onItem with errors prefixed by its index; a non-nil error from onItem
+// stops the stream and is returned. Sorted and RefineElements compare each
+// element with the last valid one and fail the later element's result.
+// Length, Min, Max, Nonempty and Includes can only be checked at the end and
+// are returned as a *StreamError. Unique is not checked, since it would need
+// every element.
+func (s *ArraySchema) ValidateStream(dec *json.Decoder, onItem func(index int, result ValidationResult) error) error {
+	token, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("expected JSON array, got %v", token)
+	}
+
+	ctx := newContext()
+	found := make([]bool, len(s.includes))
+	var prev interface{}
+	hasPrev := false
+	length := 0
+	for ; dec.More(); length++ {
+		var element interface{}
+		if err := dec.Decode(&element); err != nil {
+			return fmt.Errorf("element %d: %w", length, err)
+		}
+
+		result := validateChild(s.element, element, ctx)
+		for i := range result.Warnings {
+			result.Warnings[i] = result.Warnings[i].withPrefix(indexSegment(length))
+		}
+		for i := range result.Errors {
+			result.Errors[i] = result.Errors[i].withPrefix(indexSegment(length))
+		}
+
+		if result.Valid {
+			if hasPrev {
+				for _, refinement := range s.pairRefinements {
+					if !refinement.check(prev, result.Value) {
+						result.Valid = false
+						result.Errors = append(result.Errors, s.applyMessages([]ValidationError{{
+							Field:   fmt.Sprintf("[%d]", length),
+							Path:    []PathSegment{indexSegment(length)},
+							Message: refinement.message,
+							Code:    refinement.code,
+							Value:   result.Value,
+						}})...)
+					}
+				}
+			}
+		}
+		if result.Valid {
+			for i, required := range s.includes {
+				if !found[i] && reflect.DeepEqual(result.Value, required) {
+					found[i] = true
+				}
+			}
+			prev, hasPrev = result.Value, true
+		}
+
+		if err := onItem(length, result); err != nil {
+			return err
+		}
+	}
+
+	if _, err := dec.Token(); err != nil {
+		return err
+	}
+
+	errors := s.lengthErrors(length, nil)
+	for i, required := range s.includes {
+		if !found[i] {
+			errors = append(errors, ValidationError{
+				Message: fmt.Sprintf("array must include %v", required),
+				Code:    "missing_element",
+				Params:  map[string]interface{}{"element": required},
+			})
+		}
+	}
+	if len(errors) > 0 {
+		return &StreamError{Errors: s.applyMessages(errors)}
+	}
+	return nil
+}
+
+func (s *ArraySchema) lengthErrors(length int, value interface{}) []ValidationError {
 	var errors []ValidationError
 
 	if s.length != nil && length != *s.length {
@@ -78,6 +360,7 @@ func (s *ArraySchema) Validate(value interface{}) ValidationResult {
 			Message: fmt.Sprintf("array must have exactly %d elements", *s.length),
 			Code:    "invalid_type",
 			Value:   value,
+			Params:  map[string]interface{}{"length": *s.length},
 		})
 	}
 
@@ -86,6 +369,7 @@ func (s *ArraySchema) Validate(value interface{}) ValidationResult {
 			Message: fmt.Sprintf("array must have at least %d elements", *s.minLength),
 			Code:    "too_small",
 			Value:   value,
+			Params:  map[string]interface{}{"min": *s.minLength},
 		})
 	}
 
@@ -94,6 +378,7 @@ func (s *ArraySchema) Validate(value interface{}) ValidationResult {
 			Message: fmt.Sprintf("array must have at most %d elements", *s.maxLength),
 			Code:    "too_big",
 			Value:   value,
+			Params:  map[string]interface{}{"max": *s.maxLength},
 		})
 	}
 
@@ -102,34 +387,218 @@ func (s *ArraySchema) Validate(value interface{}) ValidationResult {
 			Message: "array must not be empty",
 			Code:    "too_small",
 			Value:   value,
+			Params:  map[string]interface{}{"min": 1},
 		})
 	}
+	return errors
+}
 
-	validatedArray := make([]interface{}, length)
-	for i := 0; i < length; i++ {
-		elementValue := v.Index(i).Interface()
-		result := s.element.Validate(elementValue)
-		if !result.Valid {
-			for _, err := range result.Errors {
-				err.Field = fmt.Sprintf("[%d]", i)
-				errors = append(errors, err)
+func findDuplicate(values []interface{}, keyFn func(interface{}) interface{}) (int, int, bool) {
+	keys := values
+	if keyFn != nil {
+		keys = make([]interface{}, len(values))
+		for i, value := range values {
+			keys[i] = keyFn(value)
+		}
+	}
+
+	seen := make(map[interface{}]int)
+	for i, key := range keys {
+		if key != nil && reflect.ValueOf(key).Comparable() {
+			if first, exists := seen[key]; exists {
+				return first, i, true
 			}
-		} else {
-			validatedArray[i] = result.Value
+			seen[key] = i
+			continue
 		}
+		for j := 0; j < i; j++ {
+			if reflect.DeepEqual(keys[j], key) {
+				return j, i, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+func compareElements(a, b interface{}) (int, bool) {
+	if x, ok := convertToFloat64(a); ok {
+		if y, ok := convertToFloat64(b); ok {
+			return cmp.Compare(x, y), true
+		}
+	}
+	if x, ok := a.(string); ok {
+		if y, ok := b.(string); ok {
+			return cmp.Compare(x, y), true
+		}
+	}
+	if x, ok := a.(time.Time); ok {
+		if y, ok := b.(time.Time); ok {
+			return x.Compare(y), true
+		}
+	}
+	return 0, false
+}
+
+func containsElement(values []interface{}, element interface{}) bool {
+	for _, value := range values {
+		if reflect.DeepEqual(value, element) {
+			return true
+		}
+	}
+	return false
+}
+
+type SetSchema struct {
+	BaseSchema
+	element  Schema
+	minSize  *int
+	maxSize  *int
+	collapse bool
+}
+
+func Set(element Schema) *SetSchema {
+	return &SetSchema{
+		BaseSchema: BaseSchema{isRequired: true},
+		element:    element,
+	}
+}
+
+func (s *SetSchema) Min(size int) *SetSchema {
+	s.minSize = &size
+	return s
+}
+
+func (s *SetSchema) Max(size int) *SetSchema {
+	s.maxSize = &size
+	return s
+}
+
+// Collapse drops duplicate elements instead of rejecting them.
+func (s *SetSchema) Collapse() *SetSchema {
+	s.collapse = true
+	return s
+}
+
+func (s *SetSchema) Optional() Schema {
+	s.BaseSchema.setOptional()
+	return s
+}
+
+func (s *SetSchema) Required() Schema {
+	s.BaseSchema.setRequired()
+	return s
+}
+
+func (s *SetSchema) Default(value interface{}) Schema {
+	s.BaseSchema.setDefault(value)
+	return s
+}
+
+func (s *SetSchema) DefaultFunc(fn func() interface{}) Schema {
+	s.BaseSchema.setDefaultFunc(fn)
+	return s
+}
+
+func (s *SetSchema) Readonly() *SetSchema {
+	s.BaseSchema.setReadonly()
+	return s
+}
+
+func (s *SetSchema) WithMessage(code, message string) *SetSchema {
+	s.BaseSchema.setMessage(code, message)
+	return s
+}
+
+func (s *SetSchema) Validate(value interface{}) ValidationResult {
+	return s.validate(value, newContext())
+}
+
+func (s *SetSchema) validate(value interface{}, ctx *validationContext) ValidationResult {
+	processedValue, shouldReturn, result := s.handleNil(value)
+	if shouldReturn {
+		return result
+	}
+
+	kind := reflect.ValueOf(processedValue).Kind()
+	if kind != reflect.Slice && kind != reflect.Array {
+		return ValidationResult{
+			Valid:  false,
+			Errors: s.applyMessages([]ValidationError{{Message: "expected set", Code: "invalid_type", Value: value, Params: map[string]interface{}{"expected": "set"}}}),
+		}
+	}
+
+	elements := &ArraySchema{
+		BaseSchema: BaseSchema{isRequired: true, messages: s.messages},
+		element:    s.element,
+		unique:     !s.collapse,
+	}
+	result = elements.validate(processedValue, ctx)
+	if !result.Valid {
+		return result
+	}
+
+	values := result.Value.([]interface{})
+	if s.collapse {
+		values = uniqueElements(values)
+	}
+
+	var errors []ValidationError
+
+	if s.minSize != nil && len(values) < *s.minSize {
+		errors = append(errors, ValidationError{
+			Message: fmt.Sprintf("set must have at least %d elements", *s.minSize),
+			Code:    "too_small",
+			Value:   value,
+			Params:  map[string]interface{}{"min": *s.minSize},
+		})
+	}
+
+	if s.maxSize != nil && len(values) > *s.maxSize {
+		errors = append(errors, ValidationError{
+			Message: fmt.Sprintf("set must have at most %d elements", *s.maxSize),
+			Code:    "too_big",
+			Value:   value,
+			Params:  map[string]interface{}{"max": *s.maxSize},
+		})
 	}
 
 	if len(errors) > 0 {
-		return ValidationResult{Valid: false, Errors: errors}
+		return ValidationResult{Valid: false, Errors: s.applyMessages(errors), Warnings: result.Warnings}
 	}
 
-	return ValidationResult{Valid: true, Value: validatedArray}
+	return ValidationResult{Valid: true, Value: values, Warnings: result.Warnings}
+}
+
+func uniqueElements(values []interface{}) []interface{} {
+	unique := make([]interface{}, 0, len(values))
+	seen := make(map[interface{}]bool)
+	for _, value := range values {
+		if value != nil && reflect.ValueOf(value).Comparable() {
+			if !seen[value] {
+				seen[value] = true
+				unique = append(unique, value)
+			}
+			continue
+		}
+		duplicate := false
+		for _, existing := range unique {
+			if reflect.DeepEqual(existing, value) {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			unique = append(unique, value)
+		}
+	}
+	return unique
 }
 
 type TupleSchema struct {
 	BaseSchema
 	elements []Schema
 	rest     Schema
+	labels   []string
 }
 
 func Tuple(elements ...Schema) *TupleSchema {
@@ -144,6 +613,23 @@ func (s *TupleSchema) Rest(schema Schema) *TupleSchema {
 	return s
 }
 
+// Labels names the fixed elements so their errors read "y: ..." instead of
+// "[1]: ...". It panics unless there is exactly one label per fixed element.
+func (s *TupleSchema) Labels(labels ...string) *TupleSchema {
+	if len(labels) != len(s.elements) {
+		panic(fmt.Sprintf("tuple has %d elements but %d labels", len(s.elements), len(labels)))
+	}
+	s.labels = labels
+	return s
+}
+
+func (s *TupleSchema) elementSegment(i int) PathSegment {
+	if i < len(s.labels) {
+		return keySegment(s.labels[i])
+	}
+	return indexSegment(i)
+}
+
 func (s *TupleSchema) Optional() Schema {
 	s.BaseSchema.setOptional()
 	return s
@@ -159,17 +645,41 @@ func (s *TupleSchema) Default(value interface{}) Schema {
 	return s
 }
 
+func (s *TupleSchema) DefaultFunc(fn func() interface{}) Schema {
+	s.BaseSchema.setDefaultFunc(fn)
+	return s
+}
+
+func (s *TupleSchema) Readonly() *TupleSchema {
+	s.BaseSchema.setReadonly()
+	return s
+}
+
+func (s *TupleSchema) WithMessage(code, message string) *TupleSchema {
+	s.BaseSchema.setMessage(code, message)
+	return s
+}
+
 func (s *TupleSchema) Validate(value interface{}) ValidationResult {
+	return s.validate(value, newContext())
+}
+
+func (s *TupleSchema) validate(value interface{}, ctx *validationContext) ValidationResult {
 	processedValue, shouldReturn, result := s.handleNil(value)
 	if shouldReturn {
 		return result
 	}
 
+	if result, ok := ctx.enter(); !ok {
+		return result
+	}
+	defer ctx.leave()
+
 	v := reflect.ValueOf(processedValue)
 	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
 		return ValidationResult{
 			Valid:  false,
-			Errors: []ValidationError{{Message: "expected tuple", Code: "invalid_type", Value: value}},
+			Errors: s.applyMessages([]ValidationError{{Message: "expected tuple", Code: "invalid_type", Value: value, Params: map[string]interface{}{"expected": "tuple"}}}),
 		}
 	}
 
@@ -181,6 +691,7 @@ func (s *TupleSchema) Validate(value interface{}) ValidationResult {
 			Message: fmt.Sprintf("tuple must have exactly %d elements", len(s.elements)),
 			Code:    "invalid_type",
 			Value:   value,
+			Params:  map[string]interface{}{"length": len(s.elements)},
 		})
 	}
 
@@ -189,21 +700,29 @@ func (s *TupleSchema) Validate(value interface{}) ValidationResult {
 			Message: fmt.Sprintf("tuple must have at least %d elements", len(s.elements)),
 			Code:    "too_small",
 			Value:   value,
+			Params:  map[string]interface{}{"min": len(s.elements)},
 		})
 	}
 
-	validatedTuple := make([]interface{}, length)
+	errors = s.applyMessages(errors)
+
+	var warnings []ValidationError
+	validatedTuple := ctx.sliceBuffer(length)
 
 	// Validate fixed elements
 	for i, elementSchema := range s.elements {
-		if i >= length {
+		if i >= length || (ctx.abortEarly && len(errors) > 0) {
 			break
 		}
 		elementValue := v.Index(i).Interface()
-		result := elementSchema.Validate(elementValue)
+		result := validateChild(elementSchema, elementValue, ctx)
+		for _, warning := range result.Warnings {
+			warning = warning.withPrefix(s.elementSegment(i))
+			warnings = append(warnings, warning)
+		}
 		if !result.Valid {
 			for _, err := range result.Errors {
-				err.Field = fmt.Sprintf("[%d]", i)
+				err = err.withPrefix(s.elementSegment(i))
 				errors = append(errors, err)
 			}
 		} else {
@@ -214,11 +733,18 @@ func (s *TupleSchema) Validate(value interface{}) ValidationResult {
 	// Validate rest elements
 	if s.rest != nil {
 		for i := len(s.elements); i < length; i++ {
+			if ctx.abortEarly && len(errors) > 0 {
+				break
+			}
 			elementValue := v.Index(i).Interface()
-			result := s.rest.Validate(elementValue)
+			result := validateChild(s.rest, elementValue, ctx)
+			for _, warning := range result.Warnings {
+				warning = warning.withPrefix(indexSegment(i))
+				warnings = append(warnings, warning)
+			}
 			if !result.Valid {
 				for _, err := range result.Errors {
-					err.Field = fmt.Sprintf("[%d]", i)
+					err = err.withPrefix(indexSegment(i))
 					errors = append(errors, err)
 				}
 			} else {
@@ -228,8 +754,8 @@ func (s *TupleSchema) Validate(value interface{}) ValidationResult {
 	}
 
 	if len(errors) > 0 {
-		return ValidationResult{Valid: false, Errors: errors}
+		return ValidationResult{Valid: false, Errors: errors, Warnings: warnings}
 	}
 
-	return ValidationResult{Valid: true, Value: validatedTuple}
-}
\ No newline at end of file
+	return ValidationResult{Valid: true, Value: validatedTuple, Warnings: warnings}
+}
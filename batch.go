@@ -0,0 +1,95 @@
+package god
+
+// BatchValidator validates many values against one schema, reusing the maps
+// and slices that hold validated output and errors between calls. A result's
+// Value and Errors are only valid until the next call to Validate; copy them
+// to keep them longer. A BatchValidator must not be used from multiple
+// goroutines at once.
+type BatchValidator struct {
+	schema Schema
+	ctx    *validationContext
+}
+
+func NewBatchValidator(schema Schema, opts ...ValidateOption) *BatchValidator {
+	ctx := &validationContext{buffers: &validationBuffers{}}
+	for _, opt := range opts {
+		opt(ctx)
+	}
+	return &BatchValidator{schema: schema, ctx: ctx}
+}
+
+func (b *BatchValidator) Validate(value interface{}) ValidationResult {
+	b.ctx.buffers.reset()
+	return b.ctx.localize(validateChild(b.schema, value, b.ctx))
+}
+
+type validationBuffers struct {
+	objects    []map[string]interface{}
+	nextObject int
+	slices     [][]interface{}
+	nextSlice  int
+	errors     [][]ValidationError
+	nextErrors int
+}
+
+func (b *validationBuffers) reset() {
+	b.nextObject = 0
+	b.nextSlice = 0
+	b.nextErrors = 0
+}
+
+func (ctx *validationContext) objectBuffer(size int) map[string]interface{} {
+	if ctx.buffers == nil {
+		return make(map[string]interface{}, size)
+	}
+	b := ctx.buffers
+	if b.nextObject == len(b.objects) {
+		b.objects = append(b.objects, make(map[string]interface{}, size))
+	}
+	obj := b.objects[b.nextObject]
+	b.nextObject++
+	clear(obj)
+	return obj
+}
+
+func (ctx *validationContext) sliceBuffer(length int) []interface{} {
+	if ctx.buffers == nil {
+		return make([]interface{}, length)
+	}
+	b := ctx.buffers
+	if b.nextSlice == len(b.slices) {
+		b.slices = append(b.slices, nil)
+	}
+	slice := b.slices[b.nextSlice]
+	if cap(slice) < length {
+		slice = make([]interface{}, length)
+		b.slices[b.nextSlice] = slice
+	}
+	b.nextSlice++
+	slice = slice[:length]
+	clear(slice)
+	return slice
+}
+
+// errorBuffer returns an empty error slice and the slot it came from. Slices
+// grown by append are handed back with keepErrors so the next call reuses the
+// larger backing array. The slot is -1 outside a BatchValidator.
+func (ctx *validationContext) errorBuffer() ([]ValidationError, int) {
+	if ctx.buffers == nil {
+		return nil, -1
+	}
+	b := ctx.buffers
+	if b.nextErrors == len(b.errors) {
+		b.errors = append(b.errors, nil)
+	}
+	slot := b.nextErrors
+	b.nextErrors++
+	return b.errors[slot][:0], slot
+}
+
+func (ctx *validationContext) keepErrors(slot int, errors []ValidationError) []ValidationError {
+	if slot >= 0 {
+		ctx.buffers.errors[slot] = errors
+	}
+	return errors
+}
@@ -8,11 +8,13 @@ import (
 
 type BooleanSchema struct {
 	BaseSchema
+	coerce bool
 }
 
 func Boolean() *BooleanSchema {
 	return &BooleanSchema{
 		BaseSchema: BaseSchema{isRequired: true},
+		coerce:     true,
 	}
 }
 
@@ -20,6 +22,20 @@ func Bool() *BooleanSchema {
 	return Boolean()
 }
 
+func CoerceBool() *BooleanSchema {
+	return Boolean().Coerce()
+}
+
+func (s *BooleanSchema) Coerce() *BooleanSchema {
+	s.coerce = true
+	return s
+}
+
+func (s *BooleanSchema) Strict() *BooleanSchema {
+	s.coerce = false
+	return s
+}
+
 func (s *BooleanSchema) Optional() Schema {
 	s.BaseSchema.setOptional()
 	return s
@@ -35,17 +51,39 @@ func (s *BooleanSchema) Default(value interface{}) Schema {
 	return s
 }
 
+func (s *BooleanSchema) DefaultFunc(fn func() interface{}) Schema {
+	s.BaseSchema.setDefaultFunc(fn)
+	return s
+}
+
+func (s *BooleanSchema) Readonly() *BooleanSchema {
+	s.BaseSchema.setReadonly()
+	return s
+}
+
+func (s *BooleanSchema) WithMessage(code, message string) *BooleanSchema {
+	s.BaseSchema.setMessage(code, message)
+	return s
+}
+
 func (s *BooleanSchema) Validate(value interface{}) ValidationResult {
+	return s.validate(value, newContext())
+}
+
+func (s *BooleanSchema) validate(value interface{}, ctx *validationContext) ValidationResult {
 	processedValue, shouldReturn, result := s.handleNil(value)
 	if shouldReturn {
 		return result
 	}
 
-	b, ok := convertToBoolean(processedValue)
+	b, ok := processedValue.(bool)
+	if !ok && (s.coerce || ctx.coerce) {
+		b, ok = convertToBoolean(processedValue)
+	}
 	if !ok {
 		return ValidationResult{
 			Valid:  false,
-			Errors: []ValidationError{{Message: "expected boolean", Code: "invalid_type", Value: value}},
+			Errors: s.applyMessages([]ValidationError{{Message: "expected boolean", Code: "invalid_type", Value: value, Params: map[string]interface{}{"expected": "boolean"}}}),
 		}
 	}
 
@@ -91,4 +129,4 @@ func convertToBoolean(value interface{}) (bool, bool) {
 		}
 	}
 	return false, false
-}
\ No newline at end of file
+}
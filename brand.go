@@ -0,0 +1,162 @@
+package god
+
+import "encoding/json"
+
+// BrandedValue tags a validated value with the brand of the schema that
+// produced it, so a UserID cannot be mistaken for an OrderID downstream.
+type BrandedValue struct {
+	Brand string
+	Value interface{}
+}
+
+// MarshalJSON encodes only the wrapped value, which keeps ParseInto and
+// Typed decoding branded fields as their underlying type.
+func (b BrandedValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(b.Value)
+}
+
+type BrandSchema struct {
+	BaseSchema
+	schema Schema
+	brand  string
+}
+
+func Brand(schema Schema, name string) *BrandSchema {
+	return &BrandSchema{
+		BaseSchema: BaseSchema{isRequired: true},
+		schema:     schema,
+		brand:      name,
+	}
+}
+
+func (s *BrandSchema) BrandName() string {
+	return s.brand
+}
+
+func (s *BrandSchema) Unwrap() Schema {
+	return s.schema
+}
+
+func (s *BrandSchema) Optional() Schema {
+	s.schema = s.schema.Optional()
+	return s
+}
+
+func (s *BrandSchema) Required() Schema {
+	s.schema = s.schema.Required()
+	return s
+}
+
+func (s *BrandSchema) Default(value interface{}) Schema {
+	s.schema = s.schema.Default(value)
+	return s
+}
+
+func (s *BrandSchema) Validate(value interface{}) ValidationResult {
+	return s.validate(value, newContext())
+}
+
+func (s *BrandSchema) validate(value interface{}, ctx *validationContext) ValidationResult {
+	result := validateChild(s.schema, value, ctx)
+	if !result.Valid || result.Value == nil {
+		return result
+	}
+	result.Value = BrandedValue{Brand: s.brand, Value: result.Value}
+	return result
+}
+
+func (s *StringSchema) Brand(name string) *BrandSchema {
+	return Brand(s, name)
+}
+
+func (s *NumberSchema) Brand(name string) *BrandSchema {
+	return Brand(s, name)
+}
+
+func (s *BigIntSchema) Brand(name string) *BrandSchema {
+	return Brand(s, name)
+}
+
+func (s *BooleanSchema) Brand(name string) *BrandSchema {
+	return Brand(s, name)
+}
+
+func (s *DateSchema) Brand(name string) *BrandSchema {
+	return Brand(s, name)
+}
+
+func (s *ObjectSchema) Brand(name string) *BrandSchema {
+	return Brand(s, name)
+}
+
+func (s *ArraySchema) Brand(name string) *BrandSchema {
+	return Brand(s, name)
+}
+
+func (s *TupleSchema) Brand(name string) *BrandSchema {
+	return Brand(s, name)
+}
+
+func (s *UnionSchema) Brand(name string) *BrandSchema {
+	return Brand(s, name)
+}
+
+func (s *DiscriminatedUnionSchema) Brand(name string) *BrandSchema {
+	return Brand(s, name)
+}
+
+func (s *LiteralSchema) Brand(name string) *BrandSchema {
+	return Brand(s, name)
+}
+
+func (s *EnumSchema) Brand(name string) *BrandSchema {
+	return Brand(s, name)
+}
+
+func (s *NullableSchema) Brand(name string) *BrandSchema {
+	return Brand(s, name)
+}
+
+func (s *AnySchema) Brand(name string) *BrandSchema {
+	return Brand(s, name)
+}
+
+func (s *UnknownSchema) Brand(name string) *BrandSchema {
+	return Brand(s, name)
+}
+
+func (s *OrderedSchema) Brand(name string) *BrandSchema {
+	return Brand(s, name)
+}
+
+func (s *TransformSchema) Brand(name string) *BrandSchema {
+	return Brand(s, name)
+}
+
+func (s *PipeSchema) Brand(name string) *BrandSchema {
+	return Brand(s, name)
+}
+
+func (s *CatchSchema) Brand(name string) *BrandSchema {
+	return Brand(s, name)
+}
+
+func (s *RecordSchema) Brand(name string) *BrandSchema {
+	return Brand(s, name)
+}
+
+func (s *MapSchema) Brand(name string) *BrandSchema {
+	return Brand(s, name)
+}
+
+func (s *SetSchema) Brand(name string) *BrandSchema {
+	return Brand(s, name)
+}
+
+func (s *NativeEnumSchema[T]) Brand(name string) *BrandSchema {
+	return Brand(s, name)
+}
+
+func (s *CustomSchema) Brand(name string) *BrandSchema {
+	return Brand(s, name)
+}
@@ -0,0 +1,136 @@
+package god
+
+// CatchSchema replaces a failed validation of its inner schema with a fixed
+// fallback value. The errors are dropped, so the result is always valid.
+type CatchSchema struct {
+	BaseSchema
+	schema   Schema
+	fallback interface{}
+}
+
+func Catch(schema Schema, fallback interface{}) *CatchSchema {
+	return &CatchSchema{
+		BaseSchema: BaseSchema{isRequired: true},
+		schema:     schema,
+		fallback:   fallback,
+	}
+}
+
+func (s *CatchSchema) Optional() Schema {
+	s.schema = s.schema.Optional()
+	return s
+}
+
+func (s *CatchSchema) Required() Schema {
+	s.schema = s.schema.Required()
+	return s
+}
+
+func (s *CatchSchema) Default(value interface{}) Schema {
+	s.schema = s.schema.Default(value)
+	return s
+}
+
+func (s *CatchSchema) Validate(value interface{}) ValidationResult {
+	return s.validate(value, newContext())
+}
+
+func (s *CatchSchema) validate(value interface{}, ctx *validationContext) ValidationResult {
+	result := validateChild(s.schema, value, ctx)
+	if result.Valid {
+		return result
+	}
+	return ValidationResult{Valid: true, Value: copyValue(s.fallback), Warnings: result.Warnings}
+}
+
+func (s *StringSchema) Catch(fallback interface{}) *CatchSchema {
+	return Catch(s, fallback)
+}
+
+func (s *NumberSchema) Catch(fallback interface{}) *CatchSchema {
+	return Catch(s, fallback)
+}
+
+func (s *BigIntSchema) Catch(fallback interface{}) *CatchSchema {
+	return Catch(s, fallback)
+}
+
+func (s *BooleanSchema) Catch(fallback interface{}) *CatchSchema {
+	return Catch(s, fallback)
+}
+
+func (s *DateSchema) Catch(fallback interface{}) *CatchSchema {
+	return Catch(s, fallback)
+}
+
+func (s *ObjectSchema) Catch(fallback interface{}) *CatchSchema {
+	return Catch(s, fallback)
+}
+
+func (s *ArraySchema) Catch(fallback interface{}) *CatchSchema {
+	return Catch(s, fallback)
+}
+
+func (s *TupleSchema) Catch(fallback interface{}) *CatchSchema {
+	return Catch(s, fallback)
+}
+
+func (s *UnionSchema) Catch(fallback interface{}) *CatchSchema {
+	return Catch(s, fallback)
+}
+
+func (s *DiscriminatedUnionSchema) Catch(fallback interface{}) *CatchSchema {
+	return Catch(s, fallback)
+}
+
+func (s *LiteralSchema) Catch(fallback interface{}) *CatchSchema {
+	return Catch(s, fallback)
+}
+
+func (s *EnumSchema) Catch(fallback interface{}) *CatchSchema {
+	return Catch(s, fallback)
+}
+
+func (s *NullableSchema) Catch(fallback interface{}) *CatchSchema {
+	return Catch(s, fallback)
+}
+
+func (s *AnySchema) Catch(fallback interface{}) *CatchSchema {
+	return Catch(s, fallback)
+}
+
+func (s *UnknownSchema) Catch(fallback interface{}) *CatchSchema {
+	return Catch(s, fallback)
+}
+
+func (s *OrderedSchema) Catch(fallback interface{}) *CatchSchema {
+	return Catch(s, fallback)
+}
+
+func (s *TransformSchema) Catch(fallback interface{}) *CatchSchema {
+	return Catch(s, fallback)
+}
+
+func (s *PipeSchema) Catch(fallback interface{}) *CatchSchema {
+	return Catch(s, fallback)
+}
+
+func (s *RecordSchema) Catch(fallback interface{}) *CatchSchema {
+	return Catch(s, fallback)
+}
+
+func (s *MapSchema) Catch(fallback interface{}) *CatchSchema {
+	return Catch(s, fallback)
+}
+
+func (s *SetSchema) Catch(fallback interface{}) *CatchSchema {
+	return Catch(s, fallback)
+}
+
+func (s *NativeEnumSchema[T]) Catch(fallback interface{}) *CatchSchema {
+	return Catch(s, fallback)
+}
+
+func (s *CustomSchema) Catch(fallback interface{}) *CatchSchema {
+	return Catch(s, fallback)
+}
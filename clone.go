@@ -0,0 +1,305 @@
+package god
+
+import (
+	"maps"
+	"slices"
+)
+
+// Modifiers of most schemas change their receiver and return it, so deriving
+// a variant from a shared schema changes the shared one too. Clone returns a
+// deep copy to derive from instead:
+//
+//	limited := tags.Clone().(*ArraySchema).Max(10) // tags is unchanged
+//
+// String, number and object modifiers copy their receiver themselves (see
+// derive). Every built-in schema has a Clone method. Child schemas are cloned
+// as well; schemas from outside this package are shared between the copies.
+// Slices are clipped rather than copied, so appending to the clone never
+// writes into the original's backing array.
+type cloner interface {
+	Clone() Schema
+}
+
+func cloneChild(schema Schema) Schema {
+	if c, ok := schema.(cloner); ok {
+		return c.Clone()
+	}
+	return schema
+}
+
+func cloneSchemas(schemas []Schema) []Schema {
+	if schemas == nil {
+		return nil
+	}
+	out := make([]Schema, len(schemas))
+	for i, schema := range schemas {
+		out[i] = cloneChild(schema)
+	}
+	return out
+}
+
+func cloneSchemaMap(schemas map[string]Schema) map[string]Schema {
+	if schemas == nil {
+		return nil
+	}
+	out := make(map[string]Schema, len(schemas))
+	for key, schema := range schemas {
+		out[key] = cloneChild(schema)
+	}
+	return out
+}
+
+func (s BaseSchema) clone() BaseSchema {
+	s.defaultValue = copyValue(s.defaultValue)
+	s.messages = maps.Clone(s.messages)
+	return s
+}
+
+func (s *StringSchema) Clone() Schema {
+	clone := *s
+	clone.BaseSchema = s.BaseSchema.clone()
+	clone.patterns = slices.Clip(s.patterns)
+	clone.groups = make([]regexGroups, len(s.groups))
+	for i, group := range s.groups {
+		clone.groups[i] = regexGroups{pattern: group.pattern, schemas: cloneSchemaMap(group.schemas)}
+	}
+	clone.transforms = slices.Clip(s.transforms)
+	clone.sanitizers = slices.Clip(s.sanitizers)
+	clone.jsonSchema = cloneChild(s.jsonSchema)
+	clone.urlOptions.schemes = slices.Clip(s.urlOptions.schemes)
+	clone.formats = slices.Clip(s.formats)
+	return &clone
+}
+
+func (s *NumberSchema) Clone() Schema {
+	clone := *s
+	clone.BaseSchema = s.BaseSchema.clone()
+	return &clone
+}
+
+func (s *BigIntSchema) Clone() Schema {
+	clone := *s
+	clone.BaseSchema = s.BaseSchema.clone()
+	return &clone
+}
+
+func (s *BooleanSchema) Clone() Schema {
+	clone := *s
+	clone.BaseSchema = s.BaseSchema.clone()
+	return &clone
+}
+
+func (s *DateSchema) Clone() Schema {
+	clone := *s
+	clone.BaseSchema = s.BaseSchema.clone()
+	clone.layouts = slices.Clip(s.layouts)
+	return &clone
+}
+
+func (s *OrderedSchema) Clone() Schema {
+	clone := *s
+	clone.BaseSchema = s.BaseSchema.clone()
+	return &clone
+}
+
+func (s *AnySchema) Clone() Schema {
+	return &AnySchema{BaseSchema: s.BaseSchema.clone()}
+}
+
+func (s *UnknownSchema) Clone() Schema {
+	return &UnknownSchema{BaseSchema: s.BaseSchema.clone()}
+}
+
+func (s *VoidSchema) Clone() Schema {
+	return &VoidSchema{BaseSchema: s.BaseSchema.clone()}
+}
+
+func (s *NeverSchema) Clone() Schema {
+	return &NeverSchema{BaseSchema: s.BaseSchema.clone()}
+}
+
+func (s *CustomSchema) Clone() Schema {
+	return &CustomSchema{BaseSchema: s.BaseSchema.clone(), fn: s.fn}
+}
+
+// Clone keeps the schema function but not its result, which is resolved
+// again on first use.
+func (s *LazySchema) Clone() Schema {
+	return &LazySchema{BaseSchema: s.BaseSchema.clone(), schemaFn: s.schemaFn}
+}
+
+func (s *ObjectSchema) Clone() Schema {
+	clone := *s
+	clone.BaseSchema = s.BaseSchema.clone()
+	clone.fields = cloneSchemaMap(s.fields)
+	clone.catchall = cloneChild(s.catchall)
+	clone.shape = cloneSchemaMap(s.shape)
+	clone.keyof = slices.Clip(s.keyof)
+	clone.required = slices.Clip(s.required)
+	clone.pick = slices.Clip(s.pick)
+	clone.omit = slices.Clip(s.omit)
+	clone.extend = cloneSchemaMap(s.extend)
+	if s.merges != nil {
+		clone.merges = make([]*ObjectSchema, len(s.merges))
+		for i, other := range s.merges {
+			clone.merges[i] = other.Clone().(*ObjectSchema)
+		}
+	}
+	clone.rename = maps.Clone(s.rename)
+	clone.refinements = slices.Clip(s.refinements)
+	clone.cache = &fieldCache{}
+	return &clone
+}
+
+func (s *RecordSchema) Clone() Schema {
+	clone := *s
+	clone.BaseSchema = s.BaseSchema.clone()
+	clone.key = cloneChild(s.key)
+	clone.value = cloneChild(s.value)
+	return &clone
+}
+
+func (s *MapSchema) Clone() Schema {
+	clone := *s
+	clone.BaseSchema = s.BaseSchema.clone()
+	clone.key = cloneChild(s.key)
+	clone.value = cloneChild(s.value)
+	return &clone
+}
+
+func (s *ArraySchema) Clone() Schema {
+	clone := *s
+	clone.BaseSchema = s.BaseSchema.clone()
+	clone.element = cloneChild(s.element)
+	clone.includes = slices.Clip(s.includes)
+	clone.pairRefinements = slices.Clip(s.pairRefinements)
+	return &clone
+}
+
+func (s *SetSchema) Clone() Schema {
+	clone := *s
+	clone.BaseSchema = s.BaseSchema.clone()
+	clone.element = cloneChild(s.element)
+	return &clone
+}
+
+func (s *TupleSchema) Clone() Schema {
+	clone := *s
+	clone.BaseSchema = s.BaseSchema.clone()
+	clone.elements = cloneSchemas(s.elements)
+	clone.rest = cloneChild(s.rest)
+	clone.labels = slices.Clip(s.labels)
+	return &clone
+}
+
+func (s *UnionSchema) Clone() Schema {
+	clone := *s
+	clone.BaseSchema = s.BaseSchema.clone()
+	clone.schemas = cloneSchemas(s.schemas)
+	return &clone
+}
+
+func (s *DiscriminatedUnionSchema) Clone() Schema {
+	clone := *s
+	clone.BaseSchema = s.BaseSchema.clone()
+	clone.options = cloneSchemaMap(s.options)
+	return &clone
+}
+
+func (s *LiteralSchema) Clone() Schema {
+	clone := *s
+	clone.BaseSchema = s.BaseSchema.clone()
+	return &clone
+}
+
+func (s *EnumSchema) Clone() Schema {
+	clone := *s
+	clone.BaseSchema = s.BaseSchema.clone()
+	clone.values = slices.Clip(s.values)
+	clone.aliases = slices.Clip(s.aliases)
+	return &clone
+}
+
+func (s *NativeEnumSchema[T]) Clone() Schema {
+	clone := *s
+	clone.BaseSchema = s.BaseSchema.clone()
+	clone.values = slices.Clip(s.values)
+	return &clone
+}
+
+func (s *NullableSchema) Clone() Schema {
+	clone := *s
+	clone.BaseSchema = s.BaseSchema.clone()
+	clone.schema = cloneChild(s.schema)
+	return &clone
+}
+
+func (s *TransformSchema) Clone() Schema {
+	clone := *s
+	clone.BaseSchema = s.BaseSchema.clone()
+	clone.schema = cloneChild(s.schema)
+	clone.transforms = slices.Clip(s.transforms)
+	return &clone
+}
+
+func (s *PreprocessSchema) Clone() Schema {
+	clone := *s
+	clone.BaseSchema = s.BaseSchema.clone()
+	clone.schema = cloneChild(s.schema)
+	return &clone
+}
+
+func (s *PipeSchema) Clone() Schema {
+	clone := *s
+	clone.BaseSchema = s.BaseSchema.clone()
+	clone.from = cloneChild(s.from)
+	clone.to = cloneChild(s.to)
+	return &clone
+}
+
+func (s *CatchSchema) Clone() Schema {
+	clone := *s
+	clone.BaseSchema = s.BaseSchema.clone()
+	clone.schema = cloneChild(s.schema)
+	clone.fallback = copyValue(s.fallback)
+	return &clone
+}
+
+func (s *BrandSchema) Clone() Schema {
+	clone := *s
+	clone.BaseSchema = s.BaseSchema.clone()
+	clone.schema = cloneChild(s.schema)
+	return &clone
+}
+
+// derive returns a shallow copy for a modifier to change, so modifiers never
+// change their receiver. Child schemas are shared with the receiver.
+func (s *StringSchema) derive() *StringSchema {
+	clone := *s
+	clone.messages = maps.Clone(s.messages)
+	clone.patterns = slices.Clip(s.patterns)
+	clone.groups = slices.Clip(s.groups)
+	clone.transforms = slices.Clip(s.transforms)
+	clone.sanitizers = slices.Clip(s.sanitizers)
+	clone.urlOptions.schemes = slices.Clip(s.urlOptions.schemes)
+	clone.formats = slices.Clip(s.formats)
+	return &clone
+}
+
+func (s *NumberSchema) derive() *NumberSchema {
+	clone := *s
+	clone.messages = maps.Clone(s.messages)
+	return &clone
+}
+
+func (s *ObjectSchema) derive() *ObjectSchema {
+	clone := *s
+	clone.messages = maps.Clone(s.messages)
+	clone.required = slices.Clip(s.required)
+	clone.extend = maps.Clone(s.extend)
+	clone.merges = slices.Clip(s.merges)
+	clone.rename = maps.Clone(s.rename)
+	clone.refinements = slices.Clip(s.refinements)
+	clone.cache = &fieldCache{}
+	return &clone
+}
@@ -0,0 +1,91 @@
+package god
+
+// CustomSchema validates with a function, for rules no built-in schema covers
+// such as a Luhn check on card numbers. It can be used anywhere a Schema is
+// accepted, including object fields, array elements and union options.
+type CustomSchema struct {
+	BaseSchema
+	fn func(value interface{}) ValidationResult
+}
+
+// NewSchema returns a schema that validates with fn. Nil input is handled
+// before fn runs, like on every built-in schema: it fails as required, passes
+// when Optional, or is replaced by the Default, which fn then validates. fn
+// never receives nil or a nil pointer.
+//
+// fn must follow the Validate contract described on Schema, with two
+// shortcuts: a valid result without a Value passes the input through, and an
+// invalid result without errors, or an error without a Code, reports
+// "invalid value" with the code "custom".
+func NewSchema(fn func(value interface{}) ValidationResult) *CustomSchema {
+	return &CustomSchema{
+		BaseSchema: BaseSchema{isRequired: true},
+		fn:         fn,
+	}
+}
+
+func (s *CustomSchema) Optional() Schema {
+	s.BaseSchema.setOptional()
+	return s
+}
+
+func (s *CustomSchema) Required() Schema {
+	s.BaseSchema.setRequired()
+	return s
+}
+
+func (s *CustomSchema) Default(value interface{}) Schema {
+	s.BaseSchema.setDefault(value)
+	return s
+}
+
+func (s *CustomSchema) DefaultFunc(fn func() interface{}) Schema {
+	s.BaseSchema.setDefaultFunc(fn)
+	return s
+}
+
+func (s *CustomSchema) Readonly() *CustomSchema {
+	s.BaseSchema.setReadonly()
+	return s
+}
+
+// WithMessage replaces the message of fn's errors with the given code, as
+// well as the message of the required error.
+func (s *CustomSchema) WithMessage(code, message string) *CustomSchema {
+	s.BaseSchema.setMessage(code, message)
+	return s
+}
+
+func (s *CustomSchema) Validate(value interface{}) ValidationResult {
+	processedValue, shouldReturn, result := s.handleNil(value)
+	if shouldReturn {
+		return result
+	}
+
+	result = s.fn(processedValue)
+	if result.Valid {
+		if result.Value == nil {
+			result.Value = processedValue
+		}
+		return result
+	}
+	if len(result.Errors) == 0 {
+		result.Errors = []ValidationError{{Message: "invalid value", Code: "custom", Value: processedValue}}
+	}
+	for i := range result.Errors {
+		if result.Errors[i].Code == "" {
+			result.Errors[i].Code = "custom"
+			if result.Errors[i].Message == "" {
+				result.Errors[i].Message = "invalid value"
+			}
+		}
+	}
+	result.Errors = s.applyMessages(result.Errors)
+	return result
+}
+
+// Custom is NewSchema for one-off rules written inline, such as
+// Object(map[string]Schema{"card": Custom(validateLuhn)}).
+func Custom(fn func(value interface{}) ValidationResult) *CustomSchema {
+	return NewSchema(fn)
+}
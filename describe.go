@@ -0,0 +1,392 @@
+package god
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// describer is implemented by every built-in schema. Describe returns a
+// one-line summary for logs and error context, such as
+// "string(min=3, max=50, email)" or "object{ age: number?, name: string }".
+// Object fields are sorted, so the output is stable, and a trailing "?"
+// marks an optional schema. Other schemas are described by their Go type.
+type describer interface {
+	Describe() string
+}
+
+func describeChild(schema Schema) string {
+	if d, ok := schema.(describer); ok {
+		return d.Describe()
+	}
+	return fmt.Sprintf("%T", schema)
+}
+
+// describe joins name and params and adds the settings every schema shares:
+// the default, the readonly marker and optionality.
+func (s *BaseSchema) describe(name string, params []string) string {
+	if s.hasDefault && s.defaultFunc != nil {
+		params = append(params, "default=func")
+	} else if s.hasDefault {
+		params = append(params, "default="+describeValue(s.defaultValue))
+	}
+	if s.readonly {
+		params = append(params, "readonly")
+	}
+	if len(params) > 0 {
+		name += "(" + strings.Join(params, ", ") + ")"
+	}
+	if s.isOptional {
+		name += "?"
+	}
+	return name
+}
+
+func describeValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return strconv.Quote(v)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case time.Time:
+		return v.Format(time.RFC3339)
+	}
+	return fmt.Sprint(value)
+}
+
+func describeList(schemas []Schema) []string {
+	var out []string
+	for _, schema := range schemas {
+		out = append(out, describeChild(schema))
+	}
+	return out
+}
+
+func (s *StringSchema) Describe() string {
+	var params []string
+	if s.minLength != nil {
+		params = append(params, fmt.Sprintf("min=%d", *s.minLength))
+	}
+	if s.maxLength != nil {
+		params = append(params, fmt.Sprintf("max=%d", *s.maxLength))
+	}
+	if s.email {
+		params = append(params, "email")
+	}
+	if s.url {
+		params = append(params, "url")
+	}
+	if s.datetime != nil {
+		params = append(params, "datetime")
+	}
+	for _, format := range s.formats {
+		params = append(params, strings.ToLower(format.name))
+	}
+	for _, pattern := range s.patterns {
+		params = append(params, "pattern=/"+pattern.String()+"/")
+	}
+	for _, group := range s.groups {
+		params = append(params, "pattern=/"+group.pattern.String()+"/")
+	}
+	if s.startsWith != nil {
+		params = append(params, "startsWith="+strconv.Quote(*s.startsWith))
+	}
+	if s.endsWith != nil {
+		params = append(params, "endsWith="+strconv.Quote(*s.endsWith))
+	}
+	if s.includes != nil {
+		params = append(params, "includes="+strconv.Quote(*s.includes))
+	}
+	if s.ascii {
+		params = append(params, "ascii")
+	}
+	if s.emoji {
+		params = append(params, "emoji")
+	}
+	if s.base64 {
+		params = append(params, "base64")
+	} else if s.base64URL {
+		params = append(params, "base64url")
+	}
+	if s.jsonSchema != nil {
+		params = append(params, "json="+describeChild(s.jsonSchema))
+	} else if s.json {
+		params = append(params, "json")
+	}
+	return s.describe("string", params)
+}
+
+func (s *NumberSchema) Describe() string {
+	name := "number"
+	if s.intKind != "" {
+		name = s.intKind
+	} else if s.int {
+		name = "int"
+	}
+
+	var params []string
+	if s.min != nil && s.minExclusive {
+		params = append(params, "gt="+describeValue(*s.min))
+	} else if s.min != nil {
+		params = append(params, "min="+describeValue(*s.min))
+	}
+	if s.max != nil && s.maxExclusive {
+		params = append(params, "lt="+describeValue(*s.max))
+	} else if s.max != nil {
+		params = append(params, "max="+describeValue(*s.max))
+	}
+	if s.clamp {
+		params = append(params, "clamp")
+	}
+	if s.positive {
+		params = append(params, "positive")
+	}
+	if s.negative {
+		params = append(params, "negative")
+	}
+	if s.nonNeg {
+		params = append(params, "nonnegative")
+	}
+	if s.nonPos {
+		params = append(params, "nonpositive")
+	}
+	if s.finite {
+		params = append(params, "finite")
+	}
+	if s.safe {
+		params = append(params, "safe")
+	}
+	if s.multipleOf != nil {
+		params = append(params, "multipleOf="+describeValue(*s.multipleOf))
+	}
+	if s.step != nil {
+		params = append(params, "step="+describeValue(*s.step))
+	}
+	if s.minDigits != nil {
+		params = append(params, fmt.Sprintf("minDigits=%d", *s.minDigits))
+	}
+	if s.maxDigits != nil {
+		params = append(params, fmt.Sprintf("maxDigits=%d", *s.maxDigits))
+	}
+	return s.describe(name, params)
+}
+
+func (s *BigIntSchema) Describe() string {
+	var params []string
+	if s.min != nil {
+		params = append(params, "min="+s.min.String())
+	}
+	if s.max != nil {
+		params = append(params, "max="+s.max.String())
+	}
+	if s.positive {
+		params = append(params, "positive")
+	}
+	return s.describe("bigint", params)
+}
+
+func (s *BooleanSchema) Describe() string {
+	return s.describe("boolean", nil)
+}
+
+func (s *DateSchema) Describe() string {
+	var params []string
+	if s.min != nil {
+		params = append(params, "min="+describeValue(*s.min))
+	}
+	if s.max != nil {
+		params = append(params, "max="+describeValue(*s.max))
+	}
+	if s.minAge != nil {
+		params = append(params, fmt.Sprintf("minAge=%d", *s.minAge))
+	}
+	if s.maxAge != nil {
+		params = append(params, fmt.Sprintf("maxAge=%d", *s.maxAge))
+	}
+	if s.past {
+		params = append(params, "past")
+	}
+	if s.future {
+		params = append(params, "future")
+	}
+	if s.dateOnly {
+		params = append(params, "dateOnly")
+	}
+	return s.describe("date", params)
+}
+
+func (s *OrderedSchema) Describe() string {
+	var params []string
+	if s.hasMin {
+		params = append(params, "min="+describeValue(s.min))
+	}
+	if s.hasMax {
+		params = append(params, "max="+describeValue(s.max))
+	}
+	return s.describe("ordered", params)
+}
+
+func (s *AnySchema) Describe() string {
+	return s.describe("any", nil)
+}
+
+func (s *UnknownSchema) Describe() string {
+	return s.describe("unknown", nil)
+}
+
+func (s *VoidSchema) Describe() string {
+	return s.describe("void", nil)
+}
+
+func (s *NeverSchema) Describe() string {
+	return s.describe("never", nil)
+}
+
+// Describe does not resolve the schema, so recursive schemas stay one line.
+func (s *LazySchema) Describe() string {
+	return s.describe("lazy", nil)
+}
+
+func (s *ObjectSchema) Describe() string {
+	fields := s.getEffectiveFields()
+	var entries []string
+	for _, name := range sortedKeys(fields) {
+		entries = append(entries, name+": "+describeChild(fields[name]))
+	}
+	name := "object{}"
+	if len(entries) > 0 {
+		name = "object{ " + strings.Join(entries, ", ") + " }"
+	}
+
+	var params []string
+	switch {
+	case s.unknownKeys == UnknownKeysStrict:
+		params = append(params, "strict")
+	case s.catchall != nil:
+		params = append(params, "catchall="+describeChild(s.catchall))
+	case s.unknownKeys == UnknownKeysPassthrough:
+		params = append(params, "passthrough")
+	}
+	return s.describe(name, params)
+}
+
+func (s *RecordSchema) Describe() string {
+	return s.describe("record<"+describeChild(s.key)+", "+describeChild(s.value)+">", nil)
+}
+
+func (s *MapSchema) Describe() string {
+	return s.describe("map<"+describeChild(s.key)+", "+describeChild(s.value)+">", nil)
+}
+
+func (s *ArraySchema) Describe() string {
+	var params []string
+	if s.length != nil {
+		params = append(params, fmt.Sprintf("length=%d", *s.length))
+	}
+	if s.minLength != nil {
+		params = append(params, fmt.Sprintf("min=%d", *s.minLength))
+	}
+	if s.maxLength != nil {
+		params = append(params, fmt.Sprintf("max=%d", *s.maxLength))
+	}
+	if s.nonempty {
+		params = append(params, "nonempty")
+	}
+	if s.unique || s.uniqueBy != nil {
+		params = append(params, "unique")
+	}
+	for _, value := range s.includes {
+		params = append(params, "includes="+describeValue(value))
+	}
+	return s.describe("array<"+describeChild(s.element)+">", params)
+}
+
+func (s *SetSchema) Describe() string {
+	var params []string
+	if s.minSize != nil {
+		params = append(params, fmt.Sprintf("min=%d", *s.minSize))
+	}
+	if s.maxSize != nil {
+		params = append(params, fmt.Sprintf("max=%d", *s.maxSize))
+	}
+	if s.collapse {
+		params = append(params, "collapse")
+	}
+	return s.describe("set<"+describeChild(s.element)+">", params)
+}
+
+func (s *TupleSchema) Describe() string {
+	elements := describeList(s.elements)
+	for i := range s.labels {
+		elements[i] = s.labels[i] + ": " + elements[i]
+	}
+	if s.rest != nil {
+		elements = append(elements, "..."+describeChild(s.rest))
+	}
+	return s.describe("["+strings.Join(elements, ", ")+"]", nil)
+}
+
+func (s *UnionSchema) Describe() string {
+	name := strings.Join(describeList(s.schemas), " | ")
+	if len(s.schemas) > 1 && (s.isOptional || s.hasDefault || s.readonly) {
+		name = "(" + name + ")"
+	}
+	return s.describe(name, nil)
+}
+
+func (s *DiscriminatedUnionSchema) Describe() string {
+	var entries []string
+	for _, key := range sortedKeys(s.options) {
+		entries = append(entries, strconv.Quote(key)+": "+describeChild(s.options[key]))
+	}
+	return s.describe("union<"+s.discriminant+">{ "+strings.Join(entries, ", ")+" }", nil)
+}
+
+func (s *LiteralSchema) Describe() string {
+	return s.describe("literal("+describeValue(s.value)+")", nil)
+}
+
+func (s *EnumSchema) Describe() string {
+	var values []string
+	for _, value := range s.values {
+		values = append(values, describeValue(value))
+	}
+	return s.describe("enum("+strings.Join(values, ", ")+")", nil)
+}
+
+func (s *NativeEnumSchema[T]) Describe() string {
+	var values []string
+	for _, value := range s.values {
+		values = append(values, describeValue(value))
+	}
+	return s.describe("enum("+strings.Join(values, ", ")+")", nil)
+}
+
+func (s *NullableSchema) Describe() string {
+	return s.describe("nullable<"+describeChild(s.schema)+">", nil)
+}
+
+func (s *TransformSchema) Describe() string {
+	return "transform<" + describeChild(s.schema) + ">"
+}
+
+func (s *PreprocessSchema) Describe() string {
+	return "preprocess<" + describeChild(s.schema) + ">"
+}
+
+func (s *PipeSchema) Describe() string {
+	return "pipe<" + describeChild(s.from) + ", " + describeChild(s.to) + ">"
+}
+
+func (s *CatchSchema) Describe() string {
+	return "catch<" + describeChild(s.schema) + ">(fallback=" + describeValue(s.fallback) + ")"
+}
+
+func (s *BrandSchema) Describe() string {
+	return "brand<" + describeChild(s.schema) + ">(" + s.brand + ")"
+}
+
+func (s *CustomSchema) Describe() string {
+	return s.describe("custom", nil)
+}
@@ -2,7 +2,6 @@ package god
 
 import (
 	"fmt"
-	"strings"
 	"time"
 )
 
@@ -180,9 +179,7 @@ func Example_transform() {
 	// Schema with transformations
 	userSchema := Object(map[string]Schema{
 		"name":     String().Trim().Min(1),
-		"email":    String().Transform(func(s string) string {
-			return strings.ToLower(strings.TrimSpace(s))
-		}).Email(),
+		"email":    String().Trim().ToLower().Email(),
 		"username": String().ToLower().Regex(`^[a-z0-9_]+$`),
 		"bio":      String().Trim().Max(100).Optional(),
 	})
@@ -264,7 +261,7 @@ func Example_array() {
 // Example_tuple demonstrates tuple validation
 func Example_tuple() {
 	// Define a coordinate tuple (x, y, z)
-	coordinateSchema := Tuple(Number(), Number(), Number())
+	coordinateSchema := Tuple(Number(), Number(), Number()).Labels("x", "y", "z")
 
 	coordinate := []interface{}{10.5, 20.3, 5.0}
 
@@ -381,4 +378,33 @@ func Example_nested() {
 	// Title: Introduction to Go Validation
 	// Author: John Doe
 	// Views: 150
-}
\ No newline at end of file
+}
+
+// Example_recursive demonstrates a self-referential category tree with Lazy
+func Example_recursive() {
+	// Lazy defers reading category until validation, after it is assigned
+	var category Schema
+	category = Object(map[string]Schema{
+		"name":          String().Min(1),
+		"subcategories": Array(Lazy(func() Schema { return category })).Optional(),
+	})
+
+	tree := map[string]interface{}{
+		"name": "Electronics",
+		"subcategories": []interface{}{
+			map[string]interface{}{
+				"name": "Computers",
+				"subcategories": []interface{}{
+					map[string]interface{}{"name": "Laptops"},
+					map[string]interface{}{"name": ""},
+				},
+			},
+		},
+	}
+
+	result := category.Validate(tree)
+	fmt.Println(result.Error())
+
+	// Output:
+	// validation failed: subcategories[0].subcategories[1].name: string must be at least 1 characters
+}
@@ -0,0 +1,52 @@
+package god
+
+import "net/url"
+
+// ValidateForm validates url.Values, such as a parsed query string or form
+// body, against schema with coercion enabled. A key with a single value is
+// passed as a scalar and a repeated key as an array. Keys whose field is an
+// Array, Set or Tuple schema are always passed as arrays, so a single
+// ?tag=go still validates against Array(String()).
+func ValidateForm(schema *ObjectSchema, values url.Values, opts ...ValidateOption) ValidationResult {
+	fields := schema.getEffectiveFields()
+	data := make(map[string]interface{}, len(values))
+	for key, list := range values {
+		if !isListSchema(fields[key]) {
+			if len(list) == 0 {
+				continue
+			}
+			if len(list) == 1 {
+				data[key] = list[0]
+				continue
+			}
+		}
+		items := make([]interface{}, len(list))
+		for i, item := range list {
+			items[i] = item
+		}
+		data[key] = items
+	}
+	return Validate(schema, data, append([]ValidateOption{WithCoerce()}, opts...)...)
+}
+
+// isListSchema reports whether schema expects a list, looking through
+// wrappers, so a single ?tags=go still arrives as a one-element list.
+func isListSchema(schema Schema) bool {
+	switch s := schema.(type) {
+	case *ArraySchema, *SetSchema, *TupleSchema:
+		return true
+	case *NullableSchema:
+		return isListSchema(s.schema)
+	case *CatchSchema:
+		return isListSchema(s.schema)
+	case *TransformSchema:
+		return isListSchema(s.schema)
+	case *PreprocessSchema:
+		return isListSchema(s.schema)
+	case *PipeSchema:
+		return isListSchema(s.from)
+	case *BrandSchema:
+		return isListSchema(s.schema)
+	}
+	return false
+}
@@ -1,15 +1,67 @@
 package god
 
 import (
+	"encoding/json"
 	"fmt"
+	"maps"
+	"reflect"
+	"slices"
 	"strings"
 )
 
 type ValidationError struct {
 	Field   string
+	Path    []PathSegment
 	Message string
 	Value   interface{}
 	Code    string
+	Params  map[string]interface{}
+	// UnionErrors holds, for an invalid_union error, the errors of every
+	// alternative indexed by its position in the union. Their paths are
+	// relative to the union value.
+	UnionErrors [][]ValidationError
+}
+
+type PathSegment struct {
+	Key     string
+	Index   int
+	IsIndex bool
+}
+
+func keySegment(key string) PathSegment {
+	return PathSegment{Key: key}
+}
+
+func indexSegment(index int) PathSegment {
+	return PathSegment{Index: index, IsIndex: true}
+}
+
+func (p PathSegment) String() string {
+	if p.IsIndex {
+		return fmt.Sprintf("[%d]", p.Index)
+	}
+	return p.Key
+}
+
+func formatPath(path []PathSegment) string {
+	var b strings.Builder
+	for i, segment := range path {
+		if i > 0 && !segment.IsIndex {
+			b.WriteByte('.')
+		}
+		b.WriteString(segment.String())
+	}
+	return b.String()
+}
+
+func (e ValidationError) withPrefix(segment PathSegment) ValidationError {
+	path := e.Path
+	if len(path) == 0 && e.Field != "" {
+		path = []PathSegment{keySegment(e.Field)}
+	}
+	e.Path = append([]PathSegment{segment}, path...)
+	e.Field = formatPath(e.Path)
+	return e
 }
 
 func (e ValidationError) Error() string {
@@ -20,9 +72,10 @@ func (e ValidationError) Error() string {
 }
 
 type ValidationResult struct {
-	Valid  bool
-	Errors []ValidationError
-	Value  interface{}
+	Valid    bool
+	Errors   []ValidationError
+	Value    interface{}
+	Warnings []ValidationError
 }
 
 func (r ValidationResult) Error() error {
@@ -36,6 +89,131 @@ func (r ValidationResult) Error() error {
 	return fmt.Errorf("validation failed: %s", strings.Join(messages, "; "))
 }
 
+// fieldKey is the key an error is grouped under: its formatted path, such as
+// "items[0].name", or "" for errors on the root value.
+func (e ValidationError) fieldKey() string {
+	if len(e.Path) > 0 {
+		return formatPath(e.Path)
+	}
+	return e.Field
+}
+
+// ErrorsByField groups error messages by field path, in the order the errors
+// were reported. Errors on the root value are grouped under "".
+func (r ValidationResult) ErrorsByField() map[string][]string {
+	byField := make(map[string][]string)
+	for _, err := range r.Errors {
+		key := err.fieldKey()
+		byField[key] = append(byField[key], err.Message)
+	}
+	return byField
+}
+
+type jsonError struct {
+	Path    string                 `json:"path"`
+	Code    string                 `json:"code"`
+	Message string                 `json:"message"`
+	Params  map[string]interface{} `json:"params,omitempty"`
+	Value   interface{}            `json:"value,omitempty"`
+}
+
+type jsonResult struct {
+	Valid    bool        `json:"valid"`
+	Errors   []jsonError `json:"errors"`
+	Warnings []jsonError `json:"warnings,omitempty"`
+}
+
+// MarshalJSON encodes the result as {"valid":...,"errors":[{"path","code",
+// "message","params"}]}. Input values are left out so they are not echoed
+// back to clients; use MarshalJSONWithValues to include them.
+func (r ValidationResult) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.toJSON(false))
+}
+
+// MarshalJSONWithValues is MarshalJSON with each error's Value included.
+func (r ValidationResult) MarshalJSONWithValues() ([]byte, error) {
+	return json.Marshal(r.toJSON(true))
+}
+
+func (r ValidationResult) toJSON(includeValues bool) jsonResult {
+	convert := func(errs []ValidationError) []jsonError {
+		out := make([]jsonError, 0, len(errs))
+		for _, err := range errs {
+			entry := jsonError{Path: err.fieldKey(), Code: err.Code, Message: err.Message, Params: err.Params}
+			if includeValues {
+				entry.Value = err.Value
+			}
+			out = append(out, entry)
+		}
+		return out
+	}
+	out := jsonResult{Valid: r.Valid, Errors: convert(r.Errors)}
+	if len(r.Warnings) > 0 {
+		out.Warnings = convert(r.Warnings)
+	}
+	return out
+}
+
+// FirstError returns the first error reported for the given field path.
+func (r ValidationResult) FirstError(field string) (ValidationError, bool) {
+	for _, err := range r.Errors {
+		if err.fieldKey() == field {
+			return err, true
+		}
+	}
+	return ValidationError{}, false
+}
+
+// Merge combines two results, such as those of schemas run on separate parts
+// of a payload. The result is valid only if both are, and errors and warnings
+// are r's followed by other's. When both values are map[string]interface{} the
+// value is a new map with other's keys taking precedence; otherwise it is r's
+// value, or other's if r's is nil. Neither result is modified.
+func (r ValidationResult) Merge(other ValidationResult) ValidationResult {
+	merged := ValidationResult{
+		Valid:    r.Valid && other.Valid,
+		Errors:   concatErrors(r.Errors, other.Errors),
+		Warnings: concatErrors(r.Warnings, other.Warnings),
+		Value:    r.Value,
+	}
+	left, leftMap := r.Value.(map[string]interface{})
+	right, rightMap := other.Value.(map[string]interface{})
+	switch {
+	case leftMap && rightMap:
+		value := maps.Clone(left)
+		maps.Copy(value, right)
+		merged.Value = value
+	case r.Value == nil:
+		merged.Value = other.Value
+	}
+	return merged
+}
+
+// concatErrors reuses either slice when the other is empty. Clipping a before
+// appending keeps b's errors out of a's backing array.
+func concatErrors(a, b []ValidationError) []ValidationError {
+	if len(a) == 0 {
+		return b
+	}
+	if len(b) == 0 {
+		return a
+	}
+	return append(slices.Clip(a), b...)
+}
+
+// Schema is implemented by every schema in this package. Types outside it can
+// implement Schema too and are then accepted anywhere a Schema is, such as in
+// Object, Array and Union; NewSchema builds one from a function. Validate
+// must:
+//
+//   - return Valid true with the validated value in Value, which later steps
+//     and enclosing schemas use in place of the input, or Valid false with at
+//     least one error;
+//   - set Code on every error, and Path for errors inside the value, so
+//     messages, ErrorsByField and FirstError work; enclosing schemas prefix
+//     the path with the field name or index;
+//   - handle nil as a missing value according to Optional, Required and
+//     Default, which return a schema with that behaviour.
 type Schema interface {
 	Validate(value interface{}) ValidationResult
 	Optional() Schema
@@ -48,6 +226,9 @@ type BaseSchema struct {
 	isRequired   bool
 	defaultValue interface{}
 	hasDefault   bool
+	defaultFunc  func() interface{}
+	messages     map[string]string
+	readonly     bool
 }
 
 func (s *BaseSchema) setOptional() {
@@ -60,15 +241,65 @@ func (s *BaseSchema) setRequired() {
 	s.isOptional = false
 }
 
+// acceptsNull reports whether an object field may be present with a null
+// value. Only a Default, which replaces it, makes null acceptable; Nullable
+// overrides this.
+func (s *BaseSchema) acceptsNull() bool {
+	return s.hasDefault
+}
+
 func (s *BaseSchema) setDefault(value interface{}) {
 	s.defaultValue = value
+	s.defaultFunc = nil
+	s.hasDefault = true
+}
+
+// setDefaultFunc makes fn produce the default on every validation that
+// receives nil. The produced value is validated like any other input.
+func (s *BaseSchema) setDefaultFunc(fn func() interface{}) {
+	s.defaultValue = nil
+	s.defaultFunc = fn
 	s.hasDefault = true
 }
 
+func (s *BaseSchema) setReadonly() {
+	s.readonly = true
+}
+
+// IsReadonly reports whether Readonly was called. Readonly is metadata for
+// generated specs: it is exported to JSON Schema as readOnly and does not
+// change validation.
+func (s *BaseSchema) IsReadonly() bool {
+	return s.readonly
+}
+
+func (s *BaseSchema) setMessage(code, message string) {
+	if s.messages == nil {
+		s.messages = make(map[string]string)
+	}
+	s.messages[code] = message
+}
+
+func (s *BaseSchema) applyMessages(errors []ValidationError) []ValidationError {
+	if len(s.messages) == 0 {
+		return errors
+	}
+	for i := range errors {
+		if message, exists := s.messages[errors[i].Code]; exists {
+			errors[i].Message = message
+		}
+	}
+	return errors
+}
+
 func (s *BaseSchema) handleNil(value interface{}) (interface{}, bool, ValidationResult) {
-	if value == nil {
+	if isNil(value) {
 		if s.hasDefault {
-			return s.defaultValue, false, ValidationResult{Valid: true, Value: s.defaultValue}
+			defaultValue := copyValue(s.defaultValue)
+			if s.defaultFunc != nil {
+				defaultValue = s.defaultFunc()
+			}
+			return defaultValue, false, ValidationResult{Valid: true, Value: defaultValue}
 		}
 		if s.isOptional {
 			return nil, true, ValidationResult{Valid: true, Value: nil}
@@ -76,13 +307,77 @@ func (s *BaseSchema) handleNil(value interface{}) (interface{}, bool, Validation
 		if s.isRequired {
 			return nil, true, ValidationResult{
 				Valid:  false,
-				Errors: []ValidationError{{Message: "field is required", Code: "required"}},
+				Errors: s.applyMessages([]ValidationError{{Message: "field is required", Code: "required"}}),
 			}
 		}
 		return nil, true, ValidationResult{
 			Valid:  false,
-			Errors: []ValidationError{{Message: "field is required", Code: "required"}},
+			Errors: s.applyMessages([]ValidationError{{Message: "field is required", Code: "required"}}),
 		}
 	}
 	return value, false, ValidationResult{}
-}
\ No newline at end of file
+}
+
+// isNil reports whether value is nil or a nil pointer such as (*User)(nil).
+// Both validate like a missing value, so a nil pointer never reaches code
+// that would dereference it.
+func isNil(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	return v.Kind() == reflect.Ptr && v.IsNil()
+}
+
+// copyValue deep-copies the maps, slices and arrays in v, so a default
+// returned from one validation can be modified without changing the schema
+// or later results. Pointers, structs and other values are returned as is.
+func copyValue(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array:
+		return copyReflectValue(rv).Interface()
+	}
+	return v
+}
+
+func copyReflectValue(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.New(v.Type()).Elem()
+		out.Set(copyReflectValue(v.Elem()))
+		return out
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			out.SetMapIndex(iter.Key(), copyReflectValue(iter.Value()))
+		}
+		return out
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(copyReflectValue(v.Index(i)))
+		}
+		return out
+	case reflect.Array:
+		out := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(copyReflectValue(v.Index(i)))
+		}
+		return out
+	}
+	return v
+}
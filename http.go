@@ -0,0 +1,114 @@
+package god
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// RequestError is returned by BindJSON. Status is the HTTP status to respond
+// with and Code tells the failure kinds apart: "unsupported_media_type",
+// "body_too_large", "read_error", "empty_body", "invalid_json" or
+// "validation_failed". Result
+// holds the validation errors when Code is "validation_failed".
+type RequestError struct {
+	Status  int
+	Code    string
+	Message string
+	Result  ValidationResult
+	Err     error
+}
+
+func (e *RequestError) Error() string {
+	return e.Message
+}
+
+func (e *RequestError) Unwrap() error {
+	return e.Err
+}
+
+// DefaultMaxBodyBytes is how large a request body BindJSON reads, unless
+// WithMaxBodyBytes changes it.
+const DefaultMaxBodyBytes = 1 << 20
+
+// BindJSON reads a JSON request body, validates it against schema and
+// returns the validated value. A missing Content-Type is accepted; any other
+// must be application/json or end in +json. Bodies larger than
+// DefaultMaxBodyBytes fail with status 413. opts apply to the validation.
+func BindJSON(r *http.Request, schema Schema, opts ...ValidateOption) (interface{}, error) {
+	ctx := newContext()
+	for _, opt := range opts {
+		opt(ctx)
+	}
+	limit := ctx.maxBody
+	if limit <= 0 {
+		limit = DefaultMaxBodyBytes
+	}
+
+	if contentType := r.Header.Get("Content-Type"); contentType != "" {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err != nil || (mediaType != "application/json" && !strings.HasSuffix(mediaType, "+json")) {
+			return nil, &RequestError{
+				Status:  http.StatusUnsupportedMediaType,
+				Code:    "unsupported_media_type",
+				Message: fmt.Sprintf("unsupported content type %q, expected application/json", contentType),
+				Err:     err,
+			}
+		}
+	}
+
+	var body []byte
+	if r.Body != nil {
+		var err error
+		body, err = io.ReadAll(http.MaxBytesReader(nil, r.Body, limit))
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return nil, &RequestError{
+				Status:  http.StatusRequestEntityTooLarge,
+				Code:    "body_too_large",
+				Message: fmt.Sprintf("request body is larger than %d bytes", tooLarge.Limit),
+				Err:     err,
+			}
+		}
+		if err != nil {
+			return nil, &RequestError{
+				Status:  http.StatusBadRequest,
+				Code:    "read_error",
+				Message: fmt.Sprintf("failed to read request body: %v", err),
+				Err:     err,
+			}
+		}
+	}
+	if len(strings.TrimSpace(string(body))) == 0 {
+		return nil, &RequestError{
+			Status:  http.StatusBadRequest,
+			Code:    "empty_body",
+			Message: "request body is empty",
+		}
+	}
+
+	var input interface{}
+	if err := json.Unmarshal(body, &input); err != nil {
+		return nil, &RequestError{
+			Status:  http.StatusBadRequest,
+			Code:    "invalid_json",
+			Message: fmt.Sprintf("malformed JSON: %v", err),
+			Err:     err,
+		}
+	}
+
+	result := Validate(schema, input, opts...)
+	if !result.Valid {
+		return nil, &RequestError{
+			Status:  http.StatusBadRequest,
+			Code:    "validation_failed",
+			Message: result.Error().Error(),
+			Result:  result,
+		}
+	}
+	return result.Value, nil
+}
@@ -0,0 +1,1044 @@
+package god
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"sort"
+	"strings"
+)
+
+var jsonSchemaAnnotations = map[string]bool{
+	"$schema":     true,
+	"$id":         true,
+	"$comment":    true,
+	"title":       true,
+	"description": true,
+	"examples":    true,
+	"readOnly":    true,
+	// discriminator is an OpenAPI hint emitted for discriminated unions; the
+	// oneOf next to it already describes the validation.
+	"discriminator": true,
+}
+
+var jsonSchemaKeywords = map[string]bool{
+	"type":                 true,
+	"properties":           true,
+	"required":             true,
+	"additionalProperties": true,
+	"minLength":            true,
+	"maxLength":            true,
+	"pattern":              true,
+	"format":               true,
+	"minimum":              true,
+	"maximum":              true,
+	"multipleOf":           true,
+	"enum":                 true,
+	"items":                true,
+	"minItems":             true,
+	"maxItems":             true,
+	"oneOf":                true,
+	"default":              true,
+	"const":                true,
+	"anyOf":                true,
+	"allOf":                true,
+	"not":                  true,
+	"exclusiveMinimum":     true,
+	"exclusiveMaximum":     true,
+	"contains":             true,
+	"uniqueItems":          true,
+	"prefixItems":          true,
+	"propertyNames":        true,
+	"contentEncoding":      true,
+	"contentMediaType":     true,
+	"contentSchema":        true,
+}
+
+func FromJSONSchema(data []byte) (Schema, error) {
+	var node interface{}
+	if err := json.Unmarshal(data, &node); err != nil {
+		return nil, fmt.Errorf("invalid JSON schema: %w", err)
+	}
+	return schemaFromJSONNode(node, "#")
+}
+
+func schemaFromJSONNode(node interface{}, path string) (Schema, error) {
+	switch n := node.(type) {
+	case bool:
+		if n {
+			return Any(), nil
+		}
+		return Never(), nil
+	case map[string]interface{}:
+		return schemaFromJSONObject(n, path)
+	default:
+		return nil, fmt.Errorf("%s: schema must be an object or boolean", path)
+	}
+}
+
+func schemaFromJSONObject(node map[string]interface{}, path string) (Schema, error) {
+	var keys []string
+	for key := range node {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if !jsonSchemaKeywords[key] && !jsonSchemaAnnotations[key] {
+			return nil, fmt.Errorf("%s: unsupported keyword '%s'", path, key)
+		}
+	}
+
+	schema, err := schemaFromJSONType(node, path)
+	if err != nil {
+		return nil, err
+	}
+
+	if defaultValue, exists := node["default"]; exists {
+		schema = schema.Default(defaultValue)
+	}
+	if readonly, _ := node["readOnly"].(bool); readonly {
+		if s, ok := schema.(interface{ setReadonly() }); ok {
+			s.setReadonly()
+		}
+	}
+
+	return schema, nil
+}
+
+func schemaFromJSONType(node map[string]interface{}, path string) (Schema, error) {
+	if values, exists := node["enum"]; exists {
+		list, ok := values.([]interface{})
+		if !ok || len(list) == 0 {
+			return nil, fmt.Errorf("%s: 'enum' must be a non-empty array", path)
+		}
+		return withJSONSiblings(Enum(list...), node, "enum", path)
+	}
+
+	if value, exists := node["const"]; exists {
+		return withJSONSiblings(Literal(value), node, "const", path)
+	}
+
+	if value, exists := node["not"]; exists {
+		// Only the empty schema, which Never exports, can be negated.
+		if inner, ok := value.(map[string]interface{}); !ok || len(inner) != 0 {
+			return nil, fmt.Errorf("%s: unsupported 'not' schema, only {} is supported", path)
+		}
+		return Never(), nil
+	}
+
+	if options, exists := node["oneOf"]; exists {
+		list, ok := options.([]interface{})
+		if !ok || len(list) == 0 {
+			return nil, fmt.Errorf("%s: 'oneOf' must be a non-empty array", path)
+		}
+		var schemas []Schema
+		for i, option := range list {
+			schema, err := schemaFromJSONNode(option, fmt.Sprintf("%s/oneOf/%d", path, i))
+			if err != nil {
+				return nil, err
+			}
+			schemas = append(schemas, schema)
+		}
+		union := Union(schemas...)
+		union.exclusive = true
+		return withJSONSiblings(union, node, "oneOf", path)
+	}
+
+	if options, exists := node["anyOf"]; exists {
+		list, ok := options.([]interface{})
+		if !ok || len(list) == 0 {
+			return nil, fmt.Errorf("%s: 'anyOf' must be a non-empty array", path)
+		}
+		var schemas []Schema
+		nullable := false
+		for i, option := range list {
+			if isJSONNullSchema(option) {
+				nullable = true
+				continue
+			}
+			schema, err := schemaFromJSONNode(option, fmt.Sprintf("%s/anyOf/%d", path, i))
+			if err != nil {
+				return nil, err
+			}
+			schemas = append(schemas, schema)
+		}
+		var schema Schema
+		switch len(schemas) {
+		case 0:
+			schema = Never()
+		case 1:
+			schema = schemas[0]
+		default:
+			schema = Union(schemas...)
+		}
+		if nullable {
+			schema = Nullable(schema)
+		}
+		return withJSONSiblings(schema, node, "anyOf", path)
+	}
+
+	if parts, exists := node["allOf"]; exists {
+		list, ok := parts.([]interface{})
+		if !ok || len(list) == 0 {
+			return nil, fmt.Errorf("%s: 'allOf' must be a non-empty array", path)
+		}
+		var schema Schema
+		for i, part := range list {
+			// Parts such as {"pattern": ...} rely on the type next to allOf.
+			if partNode, ok := part.(map[string]interface{}); ok {
+				if _, typed := partNode["type"]; !typed && node["type"] != nil {
+					partNode = maps.Clone(partNode)
+					partNode["type"] = node["type"]
+					part = partNode
+				}
+			}
+			next, err := schemaFromJSONNode(part, fmt.Sprintf("%s/allOf/%d", path, i))
+			if err != nil {
+				return nil, err
+			}
+			if schema == nil {
+				schema = next
+			} else {
+				schema = Pipe(schema, next)
+			}
+		}
+		return withJSONSiblings(schema, node, "allOf", path)
+	}
+
+	switch t := node["type"].(type) {
+	case nil:
+		if _, exists := node["properties"]; exists {
+			return objectFromJSONSchema(node, path)
+		}
+		if _, exists := node["items"]; exists {
+			return arrayFromJSONSchema(node, path)
+		}
+		if _, exists := node["prefixItems"]; exists {
+			return arrayFromJSONSchema(node, path)
+		}
+		return Any(), nil
+	case string:
+		return schemaFromJSONTypeName(t, node, path)
+	case []interface{}:
+		var schemas []Schema
+		nullable := false
+		for _, item := range t {
+			name, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s: 'type' entries must be strings", path)
+			}
+			if name == "null" {
+				nullable = true
+				continue
+			}
+			schema, err := schemaFromJSONTypeName(name, node, path)
+			if err != nil {
+				return nil, err
+			}
+			schemas = append(schemas, schema)
+		}
+		var schema Schema
+		switch len(schemas) {
+		case 0:
+			return schemaFromJSONTypeName("null", node, path)
+		case 1:
+			schema = schemas[0]
+		default:
+			schema = Union(schemas...)
+		}
+		if nullable {
+			return Nullable(schema), nil
+		}
+		return schema, nil
+	default:
+		return nil, fmt.Errorf("%s: 'type' must be a string or an array of strings", path)
+	}
+}
+
+// withJSONSiblings combines a schema built from enum, const or a combinator
+// with the other keywords next to it, such as type or properties, so a value
+// must satisfy both. Combinators run second, after siblings that keep unknown
+// object keys; enum and const run first to compare the raw JSON value.
+func withJSONSiblings(schema Schema, node map[string]interface{}, keyword, path string) (Schema, error) {
+	rest := make(map[string]interface{}, len(node))
+	constrained := false
+	for key, value := range node {
+		if key == keyword || key == "default" || jsonSchemaAnnotations[key] {
+			continue
+		}
+		rest[key] = value
+		constrained = true
+	}
+	if !constrained {
+		return schema, nil
+	}
+	siblings, err := schemaFromJSONType(rest, path)
+	if err != nil {
+		return nil, err
+	}
+	if keyword == "enum" || keyword == "const" {
+		return Pipe(schema, siblings), nil
+	}
+	return Pipe(siblings, schema), nil
+}
+
+// isJSONNullSchema reports whether node is {"type": "null"}, the branch
+// Nullable adds to anyOf.
+func isJSONNullSchema(node interface{}) bool {
+	m, ok := node.(map[string]interface{})
+	return ok && len(m) == 1 && m["type"] == "null"
+}
+
+func schemaFromJSONTypeName(name string, node map[string]interface{}, path string) (Schema, error) {
+	switch name {
+	case "string":
+		return stringFromJSONSchema(node, path)
+	case "number":
+		return numberFromJSONSchema(Number(), node, path)
+	case "integer":
+		return numberFromJSONSchema(Int(), node, path)
+	case "boolean":
+		return Boolean(), nil
+	case "object":
+		return objectFromJSONSchema(node, path)
+	case "array":
+		return arrayFromJSONSchema(node, path)
+	case "null":
+		return Nullable(Never()), nil
+	default:
+		return nil, fmt.Errorf("%s: unsupported type '%s'", path, name)
+	}
+}
+
+func stringFromJSONSchema(node map[string]interface{}, path string) (Schema, error) {
+	schema := String()
+
+	if value, exists := node["minLength"]; exists {
+		n, err := jsonSchemaInt(value, path, "minLength")
+		if err != nil {
+			return nil, err
+		}
+		schema = schema.Min(n)
+	}
+
+	if value, exists := node["maxLength"]; exists {
+		n, err := jsonSchemaInt(value, path, "maxLength")
+		if err != nil {
+			return nil, err
+		}
+		schema = schema.Max(n)
+	}
+
+	if value, exists := node["pattern"]; exists {
+		pattern, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s: 'pattern' must be a string", path)
+		}
+		var err error
+		if schema, err = schema.RegexErr(pattern); err != nil {
+			return nil, fmt.Errorf("%s: invalid pattern: %w", path, err)
+		}
+	}
+
+	if value, exists := node["format"]; exists {
+		format, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s: 'format' must be a string", path)
+		}
+		switch format {
+		case "email":
+			schema = schema.Email()
+		case "uri", "url":
+			schema = schema.URL()
+		case "uri-reference":
+			schema = schema.URL().AllowRelative()
+		case "date-time":
+			schema = schema.Datetime()
+		case "uuid":
+			schema = schema.UUID()
+		default:
+			return nil, fmt.Errorf("%s: unsupported format '%s'", path, format)
+		}
+	}
+
+	if value, exists := node["contentEncoding"]; exists {
+		switch value {
+		case "base64":
+			schema = schema.Base64()
+		case "base64url":
+			schema = schema.Base64URL()
+		default:
+			return nil, fmt.Errorf("%s: unsupported contentEncoding %v", path, value)
+		}
+	}
+
+	if value, exists := node["contentMediaType"]; exists {
+		if value != "application/json" {
+			return nil, fmt.Errorf("%s: unsupported contentMediaType %v", path, value)
+		}
+		schema = schema.JSON()
+		if content, exists := node["contentSchema"]; exists {
+			inner, err := schemaFromJSONNode(content, path+"/contentSchema")
+			if err != nil {
+				return nil, err
+			}
+			schema = schema.JSONSchema(inner)
+		}
+	}
+
+	return schema, nil
+}
+
+func numberFromJSONSchema(schema *NumberSchema, node map[string]interface{}, path string) (Schema, error) {
+	if value, exists := node["minimum"]; exists {
+		n, ok := value.(float64)
+		if !ok {
+			return nil, fmt.Errorf("%s: 'minimum' must be a number", path)
+		}
+		schema = schema.Min(n)
+	}
+
+	if value, exists := node["maximum"]; exists {
+		n, ok := value.(float64)
+		if !ok {
+			return nil, fmt.Errorf("%s: 'maximum' must be a number", path)
+		}
+		schema = schema.Max(n)
+	}
+
+	if value, exists := node["exclusiveMinimum"]; exists {
+		n, ok := value.(float64)
+		if !ok {
+			return nil, fmt.Errorf("%s: 'exclusiveMinimum' must be a number", path)
+		}
+		schema = schema.Gt(n)
+	}
+
+	if value, exists := node["exclusiveMaximum"]; exists {
+		n, ok := value.(float64)
+		if !ok {
+			return nil, fmt.Errorf("%s: 'exclusiveMaximum' must be a number", path)
+		}
+		schema = schema.Lt(n)
+	}
+
+	if value, exists := node["multipleOf"]; exists {
+		n, ok := value.(float64)
+		if !ok || n <= 0 {
+			return nil, fmt.Errorf("%s: 'multipleOf' must be a positive number", path)
+		}
+		schema = schema.MultipleOf(n)
+	}
+
+	return schema, nil
+}
+
+func objectFromJSONSchema(node map[string]interface{}, path string) (Schema, error) {
+	if names, exists := node["propertyNames"]; exists {
+		if _, hasProperties := node["properties"]; hasProperties {
+			return nil, fmt.Errorf("%s: 'propertyNames' is only supported without 'properties'", path)
+		}
+		keys, err := schemaFromJSONNode(names, path+"/propertyNames")
+		if err != nil {
+			return nil, err
+		}
+		var values Schema = Any()
+		if additional, exists := node["additionalProperties"]; exists {
+			if values, err = schemaFromJSONNode(additional, path+"/additionalProperties"); err != nil {
+				return nil, err
+			}
+		}
+		return Record(keys, values), nil
+	}
+
+	fields := make(map[string]Schema)
+
+	if value, exists := node["properties"]; exists {
+		properties, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s: 'properties' must be an object", path)
+		}
+		for name, property := range properties {
+			schema, err := schemaFromJSONNode(property, fmt.Sprintf("%s/properties/%s", path, name))
+			if err != nil {
+				return nil, err
+			}
+			fields[name] = schema
+		}
+	}
+
+	required := make(map[string]bool)
+	if value, exists := node["required"]; exists {
+		list, ok := value.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s: 'required' must be an array", path)
+		}
+		for _, item := range list {
+			name, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s: 'required' entries must be strings", path)
+			}
+			if _, exists := fields[name]; !exists {
+				fields[name] = Any()
+			}
+			required[name] = true
+		}
+	}
+
+	for name, schema := range fields {
+		if !required[name] {
+			fields[name] = schema.Optional()
+		}
+	}
+
+	schema := Object(fields)
+
+	switch additional := node["additionalProperties"].(type) {
+	case nil:
+		schema = schema.Passthrough()
+	case bool:
+		if additional {
+			schema = schema.Passthrough()
+		} else {
+			schema = schema.Strict()
+		}
+	default:
+		catchall, err := schemaFromJSONNode(additional, path+"/additionalProperties")
+		if err != nil {
+			return nil, err
+		}
+		schema = schema.Catchall(catchall)
+	}
+
+	return schema, nil
+}
+
+func arrayFromJSONSchema(node map[string]interface{}, path string) (Schema, error) {
+	if _, exists := node["prefixItems"]; exists {
+		return tupleFromJSONSchema(node, path)
+	}
+
+	var element Schema = Any()
+	if value, exists := node["items"]; exists {
+		schema, err := schemaFromJSONNode(value, path+"/items")
+		if err != nil {
+			return nil, err
+		}
+		element = schema
+	}
+
+	schema := Array(element)
+
+	if value, exists := node["minItems"]; exists {
+		n, err := jsonSchemaInt(value, path, "minItems")
+		if err != nil {
+			return nil, err
+		}
+		schema.Min(n)
+	}
+
+	if value, exists := node["maxItems"]; exists {
+		n, err := jsonSchemaInt(value, path, "maxItems")
+		if err != nil {
+			return nil, err
+		}
+		schema.Max(n)
+	}
+
+	if value, exists := node["uniqueItems"]; exists {
+		unique, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%s: 'uniqueItems' must be a boolean", path)
+		}
+		if unique {
+			schema.Unique()
+		}
+	}
+
+	if value, exists := node["contains"]; exists {
+		// Only the {"const": x} form that Includes exports is supported.
+		contains, ok := value.(map[string]interface{})
+		if _, hasConst := contains["const"]; !ok || !hasConst || len(contains) != 1 {
+			return nil, fmt.Errorf("%s: unsupported 'contains' schema, only {\"const\": value} is supported", path)
+		}
+		schema.Includes(contains["const"])
+	}
+
+	return schema, nil
+}
+
+// tupleFromJSONSchema reads prefixItems as tuple elements and items, unless it
+// is false, as the schema for any rest elements.
+func tupleFromJSONSchema(node map[string]interface{}, path string) (Schema, error) {
+	list, ok := node["prefixItems"].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s: 'prefixItems' must be an array", path)
+	}
+
+	elements := make([]Schema, len(list))
+	for i, item := range list {
+		element, err := schemaFromJSONNode(item, fmt.Sprintf("%s/prefixItems/%d", path, i))
+		if err != nil {
+			return nil, err
+		}
+		elements[i] = element
+	}
+
+	schema := Tuple(elements...)
+	if value, exists := node["items"]; exists && value != false {
+		rest, err := schemaFromJSONNode(value, path+"/items")
+		if err != nil {
+			return nil, err
+		}
+		schema.Rest(rest)
+	}
+	return schema, nil
+}
+
+func jsonSchemaInt(value interface{}, path, keyword string) (int, error) {
+	n, ok := value.(float64)
+	if !ok || n < 0 || !isInteger(n) {
+		return 0, fmt.Errorf("%s: '%s' must be a non-negative integer", path, keyword)
+	}
+	return int(n), nil
+}
+
+type jsonSchemaExporter interface {
+	ToJSONSchema() (map[string]interface{}, error)
+}
+
+func childJSONSchema(schema Schema) (map[string]interface{}, error) {
+	exporter, ok := schema.(jsonSchemaExporter)
+	if !ok {
+		return nil, fmt.Errorf("schema type %T cannot be converted to JSON Schema", schema)
+	}
+	return exporter.ToJSONSchema()
+}
+
+func (s *BaseSchema) annotateJSONSchema(out map[string]interface{}) map[string]interface{} {
+	// A DefaultFunc default is produced at validation time, so it has no
+	// fixed value to export.
+	if s.hasDefault && s.defaultFunc == nil {
+		out["default"] = s.defaultValue
+	}
+	if s.readonly {
+		out["readOnly"] = true
+	}
+	return out
+}
+
+func (s *BaseSchema) acceptsMissing() bool {
+	return s.isOptional || s.hasDefault
+}
+
+func (s *StringSchema) ToJSONSchema() (map[string]interface{}, error) {
+	out := map[string]interface{}{"type": "string"}
+	if s.minLength != nil {
+		out["minLength"] = *s.minLength
+	}
+	if s.maxLength != nil {
+		out["maxLength"] = *s.maxLength
+	}
+	var patterns []string
+	for _, pattern := range s.patterns {
+		patterns = append(patterns, pattern.String())
+	}
+	for _, group := range s.groups {
+		patterns = append(patterns, group.pattern.String())
+	}
+	if len(patterns) == 1 {
+		out["pattern"] = patterns[0]
+	} else if len(patterns) > 1 {
+		var all []interface{}
+		for _, pattern := range patterns {
+			all = append(all, map[string]interface{}{"pattern": pattern})
+		}
+		out["allOf"] = all
+	}
+	switch {
+	case s.email:
+		out["format"] = "email"
+	case s.url && s.urlOptions.allowRelative:
+		out["format"] = "uri-reference"
+	case s.url:
+		out["format"] = "uri"
+	case s.datetime != nil:
+		out["format"] = "date-time"
+	}
+	for _, format := range s.formats {
+		if format.jsonFormat != "" {
+			out["format"] = format.jsonFormat
+		} else if _, exists := out["pattern"]; !exists {
+			out["pattern"] = format.pattern.String()
+		}
+	}
+	if s.base64 {
+		out["contentEncoding"] = "base64"
+	} else if s.base64URL {
+		out["contentEncoding"] = "base64url"
+	}
+	if s.json {
+		out["contentMediaType"] = "application/json"
+	}
+	if s.jsonSchema != nil {
+		content, err := childJSONSchema(s.jsonSchema)
+		if err != nil {
+			return nil, err
+		}
+		out["contentSchema"] = content
+	}
+	return s.annotateJSONSchema(out), nil
+}
+
+func (s *NumberSchema) ToJSONSchema() (map[string]interface{}, error) {
+	out := map[string]interface{}{"type": "number"}
+	if s.int {
+		out["type"] = "integer"
+	}
+	if s.min != nil && s.minExclusive {
+		out["exclusiveMinimum"] = *s.min
+	} else if s.min != nil && !s.clamp {
+		// Clamped bounds accept any number, so they are not exported.
+		out["minimum"] = *s.min
+	} else if s.nonNeg {
+		out["minimum"] = 0
+	}
+	if s.max != nil && s.maxExclusive {
+		out["exclusiveMaximum"] = *s.max
+	} else if s.max != nil && !s.clamp {
+		out["maximum"] = *s.max
+	} else if s.nonPos {
+		out["maximum"] = 0
+	}
+	if s.positive {
+		out["exclusiveMinimum"] = 0
+	}
+	if s.negative {
+		out["exclusiveMaximum"] = 0
+	}
+	if s.intKind != "" {
+		// Fixed-width kinds bound the value even when no Min or Max is set.
+		limits := integerLimits[s.intKind]
+		if out["minimum"] == nil && out["exclusiveMinimum"] == nil {
+			out["minimum"] = limits[0]
+		}
+		if out["maximum"] == nil && out["exclusiveMaximum"] == nil {
+			out["maximum"] = limits[1]
+		}
+	}
+	if s.multipleOf != nil {
+		out["multipleOf"] = *s.multipleOf
+	}
+	// JSON Schema has no offset for multipleOf, so a Step is only exported
+	// when its base lies on the step grid.
+	if s.step != nil && isMultipleOf(s.stepBase, *s.step) {
+		if out["multipleOf"] == nil {
+			out["multipleOf"] = *s.step
+		} else {
+			out["allOf"] = []interface{}{map[string]interface{}{"multipleOf": *s.step}}
+		}
+	}
+	return s.annotateJSONSchema(out), nil
+}
+
+func (s *BigIntSchema) ToJSONSchema() (map[string]interface{}, error) {
+	out := map[string]interface{}{"type": "integer"}
+	if s.min != nil {
+		out["minimum"] = s.min
+	} else if s.positive {
+		out["exclusiveMinimum"] = 0
+	}
+	if s.max != nil {
+		out["maximum"] = s.max
+	}
+	return s.annotateJSONSchema(out), nil
+}
+
+func (s *BooleanSchema) ToJSONSchema() (map[string]interface{}, error) {
+	return s.annotateJSONSchema(map[string]interface{}{"type": "boolean"}), nil
+}
+
+func (s *DateSchema) ToJSONSchema() (map[string]interface{}, error) {
+	out := map[string]interface{}{"type": "string", "format": "date-time"}
+	return s.annotateJSONSchema(out), nil
+}
+
+func (s *AnySchema) ToJSONSchema() (map[string]interface{}, error) {
+	return s.annotateJSONSchema(map[string]interface{}{}), nil
+}
+
+func (s *UnknownSchema) ToJSONSchema() (map[string]interface{}, error) {
+	return s.annotateJSONSchema(map[string]interface{}{}), nil
+}
+
+func (s *VoidSchema) ToJSONSchema() (map[string]interface{}, error) {
+	return s.annotateJSONSchema(map[string]interface{}{}), nil
+}
+
+func (s *NeverSchema) ToJSONSchema() (map[string]interface{}, error) {
+	return map[string]interface{}{"not": map[string]interface{}{}}, nil
+}
+
+func (s *LazySchema) ToJSONSchema() (map[string]interface{}, error) {
+	return nil, fmt.Errorf("lazy schemas cannot be converted to JSON Schema")
+}
+
+func (s *ObjectSchema) ToJSONSchema() (map[string]interface{}, error) {
+	fields := s.getEffectiveFields()
+
+	var names []string
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	properties := make(map[string]interface{})
+	var required []string
+	for _, name := range names {
+		property, err := childJSONSchema(fields[name])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		properties[name] = property
+		if !schemaAcceptsMissing(fields[name]) {
+			required = append(required, name)
+		}
+	}
+
+	out := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		out["required"] = required
+	}
+
+	switch {
+	case s.unknownKeys == UnknownKeysStrict:
+		out["additionalProperties"] = false
+	case s.catchall != nil:
+		catchall, err := childJSONSchema(s.catchall)
+		if err != nil {
+			return nil, err
+		}
+		out["additionalProperties"] = catchall
+	case s.unknownKeys == UnknownKeysPassthrough:
+		out["additionalProperties"] = true
+	}
+
+	return s.annotateJSONSchema(out), nil
+}
+
+func schemaAcceptsMissing(schema Schema) bool {
+	switch s := schema.(type) {
+	case *NullableSchema:
+		return s.acceptsMissing()
+	case *TransformSchema:
+		return schemaAcceptsMissing(s.schema)
+	case *PreprocessSchema:
+		return schemaAcceptsMissing(s.schema)
+	case *PipeSchema:
+		return schemaAcceptsMissing(s.from)
+	case *CatchSchema:
+		return true
+	case *BrandSchema:
+		return schemaAcceptsMissing(s.schema)
+	case interface{ acceptsMissing() bool }:
+		return s.acceptsMissing()
+	}
+	return false
+}
+
+func (s *RecordSchema) ToJSONSchema() (map[string]interface{}, error) {
+	keys, err := childJSONSchema(s.key)
+	if err != nil {
+		return nil, err
+	}
+	values, err := childJSONSchema(s.value)
+	if err != nil {
+		return nil, err
+	}
+	out := map[string]interface{}{
+		"type":                 "object",
+		"propertyNames":        keys,
+		"additionalProperties": values,
+	}
+	return s.annotateJSONSchema(out), nil
+}
+
+func (s *MapSchema) ToJSONSchema() (map[string]interface{}, error) {
+	return nil, fmt.Errorf("map schemas cannot be converted to JSON Schema, use Record for string keys")
+}
+
+func (s *ArraySchema) ToJSONSchema() (map[string]interface{}, error) {
+	items, err := childJSONSchema(s.element)
+	if err != nil {
+		return nil, err
+	}
+
+	out := map[string]interface{}{"type": "array", "items": items}
+	if s.minLength != nil {
+		out["minItems"] = *s.minLength
+	} else if s.nonempty {
+		out["minItems"] = 1
+	}
+	if s.maxLength != nil {
+		out["maxItems"] = *s.maxLength
+	}
+	if s.length != nil {
+		out["minItems"] = *s.length
+		out["maxItems"] = *s.length
+	}
+	if len(s.includes) == 1 {
+		out["contains"] = map[string]interface{}{"const": s.includes[0]}
+	} else if len(s.includes) > 1 {
+		var all []interface{}
+		for _, required := range s.includes {
+			all = append(all, map[string]interface{}{"contains": map[string]interface{}{"const": required}})
+		}
+		out["allOf"] = all
+	}
+	return s.annotateJSONSchema(out), nil
+}
+
+func (s *SetSchema) ToJSONSchema() (map[string]interface{}, error) {
+	items, err := childJSONSchema(s.element)
+	if err != nil {
+		return nil, err
+	}
+
+	out := map[string]interface{}{"type": "array", "items": items}
+	if !s.collapse {
+		out["uniqueItems"] = true
+	}
+	if s.minSize != nil {
+		out["minItems"] = *s.minSize
+	}
+	if s.maxSize != nil {
+		out["maxItems"] = *s.maxSize
+	}
+	return s.annotateJSONSchema(out), nil
+}
+
+func (s *TupleSchema) ToJSONSchema() (map[string]interface{}, error) {
+	var prefixItems []interface{}
+	for i, element := range s.elements {
+		item, err := childJSONSchema(element)
+		if err != nil {
+			return nil, fmt.Errorf("[%d]: %w", i, err)
+		}
+		prefixItems = append(prefixItems, item)
+	}
+
+	out := map[string]interface{}{
+		"type":        "array",
+		"prefixItems": prefixItems,
+		"minItems":    len(s.elements),
+	}
+	if s.rest != nil {
+		rest, err := childJSONSchema(s.rest)
+		if err != nil {
+			return nil, err
+		}
+		out["items"] = rest
+	} else {
+		out["items"] = false
+	}
+	return s.annotateJSONSchema(out), nil
+}
+
+func (s *UnionSchema) ToJSONSchema() (map[string]interface{}, error) {
+	var anyOf []interface{}
+	for _, schema := range s.schemas {
+		option, err := childJSONSchema(schema)
+		if err != nil {
+			return nil, err
+		}
+		anyOf = append(anyOf, option)
+	}
+	if s.exclusive {
+		return s.annotateJSONSchema(map[string]interface{}{"oneOf": anyOf}), nil
+	}
+	return s.annotateJSONSchema(map[string]interface{}{"anyOf": anyOf}), nil
+}
+
+func (s *DiscriminatedUnionSchema) ToJSONSchema() (map[string]interface{}, error) {
+	keys := sortedKeys(s.options)
+
+	var oneOf []interface{}
+	for _, key := range keys {
+		option, err := childJSONSchema(s.options[key])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		oneOf = append(oneOf, option)
+	}
+
+	out := map[string]interface{}{"oneOf": oneOf}
+	if !strings.Contains(s.discriminant, ".") {
+		out["discriminator"] = map[string]interface{}{"propertyName": s.discriminant}
+	}
+	return s.annotateJSONSchema(out), nil
+}
+
+func (s *LiteralSchema) ToJSONSchema() (map[string]interface{}, error) {
+	return s.annotateJSONSchema(map[string]interface{}{"const": s.value}), nil
+}
+
+func (s *EnumSchema) ToJSONSchema() (map[string]interface{}, error) {
+	values := append([]interface{}{}, s.values...)
+	for _, alias := range s.aliases {
+		values = append(values, alias.alias)
+	}
+	return s.annotateJSONSchema(map[string]interface{}{"enum": values}), nil
+}
+
+func (s *NativeEnumSchema[T]) ToJSONSchema() (map[string]interface{}, error) {
+	var values []interface{}
+	for _, value := range s.values {
+		values = append(values, value)
+	}
+	return s.annotateJSONSchema(map[string]interface{}{"enum": values}), nil
+}
+
+func (s *NullableSchema) ToJSONSchema() (map[string]interface{}, error) {
+	inner, err := childJSONSchema(s.schema)
+	if err != nil {
+		return nil, err
+	}
+	out := map[string]interface{}{
+		"anyOf": []interface{}{inner, map[string]interface{}{"type": "null"}},
+	}
+	return s.annotateJSONSchema(out), nil
+}
+
+func (s *TransformSchema) ToJSONSchema() (map[string]interface{}, error) {
+	return childJSONSchema(s.schema)
+}
+
+func (s *PreprocessSchema) ToJSONSchema() (map[string]interface{}, error) {
+	return childJSONSchema(s.schema)
+}
+
+func (s *PipeSchema) ToJSONSchema() (map[string]interface{}, error) {
+	return childJSONSchema(s.from)
+}
+
+func (s *CatchSchema) ToJSONSchema() (map[string]interface{}, error) {
+	out, err := childJSONSchema(s.schema)
+	if err != nil {
+		return nil, err
+	}
+	out["default"] = s.fallback
+	return out, nil
+}
+
+func (s *BrandSchema) ToJSONSchema() (map[string]interface{}, error) {
+	return childJSONSchema(s.schema)
+}
+
+// ToJSONSchema cannot express the validation function, so a custom schema
+// accepts any value in the exported schema.
+func (s *CustomSchema) ToJSONSchema() (map[string]interface{}, error) {
+	return s.annotateJSONSchema(map[string]interface{}{}), nil
+}
@@ -1,24 +1,37 @@
 package god
 
 import (
+	"encoding/json"
 	"fmt"
 	"math"
+	"math/big"
 	"reflect"
 	"strconv"
+	"strings"
 )
 
 type NumberSchema struct {
 	BaseSchema
-	min       *float64
-	max       *float64
-	int       bool
-	positive  bool
-	negative  bool
-	nonNeg    bool
-	nonPos    bool
-	finite    bool
-	safe      bool
-	multipleOf *float64
+	min          *float64
+	max          *float64
+	minExclusive bool
+	maxExclusive bool
+	int          bool
+	intKind      string
+	positive     bool
+	negative     bool
+	nonNeg       bool
+	nonPos       bool
+	finite       bool
+	safe         bool
+	multipleOf   *float64
+	step         *float64
+	stepBase     float64
+	minDigits    *int
+	maxDigits    *int
+	coerce       bool
+	between      bool
+	clamp        bool
 }
 
 func Number() *NumberSchema {
@@ -34,6 +47,28 @@ func Int() *NumberSchema {
 	}
 }
 
+func Int32() *NumberSchema {
+	schema := Int()
+	schema.intKind = "int32"
+	return schema
+}
+
+func Int64() *NumberSchema {
+	schema := Int()
+	schema.intKind = "int64"
+	return schema
+}
+
+func Uint() *NumberSchema {
+	schema := Int()
+	schema.intKind = "uint"
+	return schema
+}
+
+func CoerceNumber() *NumberSchema {
+	return Number().Coerce()
+}
+
 func Float() *NumberSchema {
 	return &NumberSchema{
 		BaseSchema: BaseSchema{isRequired: true},
@@ -41,103 +76,290 @@ func Float() *NumberSchema {
 	}
 }
 
+func (s *NumberSchema) Coerce() *NumberSchema {
+	s = s.derive()
+	s.coerce = true
+	return s
+}
+
 func (s *NumberSchema) Min(value float64) *NumberSchema {
+	return s.Gte(value)
+}
+
+func (s *NumberSchema) Max(value float64) *NumberSchema {
+	return s.Lte(value)
+}
+
+func (s *NumberSchema) Gt(value float64) *NumberSchema {
+	s = s.derive()
+	s.between = false
 	s.min = &value
+	s.minExclusive = true
 	return s
 }
 
-func (s *NumberSchema) Max(value float64) *NumberSchema {
+func (s *NumberSchema) Gte(value float64) *NumberSchema {
+	s = s.derive()
+	s.between = false
+	s.min = &value
+	s.minExclusive = false
+	return s
+}
+
+func (s *NumberSchema) Lt(value float64) *NumberSchema {
+	s = s.derive()
+	s.between = false
+	s.max = &value
+	s.maxExclusive = true
+	return s
+}
+
+func (s *NumberSchema) Lte(value float64) *NumberSchema {
+	s = s.derive()
+	s.between = false
 	s.max = &value
+	s.maxExclusive = false
+	return s
+}
+
+// Between is Min(min).Max(max) with a single "between" error message for
+// values on either side of the range.
+func (s *NumberSchema) Between(min, max float64) *NumberSchema {
+	s = s.Min(min).Max(max)
+	s.between = true
+	return s
+}
+
+// Clamp replaces values below Min or above Max with that bound instead of
+// reporting an error, so Int().Between(1, 100).Clamp() turns 500 into 100.
+// Exclusive bounds set with Gt and Lt have no closest value and still fail.
+func (s *NumberSchema) Clamp() *NumberSchema {
+	s = s.derive()
+	s.clamp = true
 	return s
 }
 
 func (s *NumberSchema) Positive() *NumberSchema {
+	s = s.derive()
 	s.positive = true
 	return s
 }
 
 func (s *NumberSchema) Negative() *NumberSchema {
+	s = s.derive()
 	s.negative = true
 	return s
 }
 
 func (s *NumberSchema) NonNegative() *NumberSchema {
+	s = s.derive()
 	s.nonNeg = true
 	return s
 }
 
 func (s *NumberSchema) NonPositive() *NumberSchema {
+	s = s.derive()
 	s.nonPos = true
 	return s
 }
 
 func (s *NumberSchema) Finite() *NumberSchema {
+	s = s.derive()
 	s.finite = true
 	return s
 }
 
 func (s *NumberSchema) Safe() *NumberSchema {
+	s = s.derive()
 	s.safe = true
 	return s
 }
 
 func (s *NumberSchema) MultipleOf(value float64) *NumberSchema {
+	s = s.derive()
 	s.multipleOf = &value
 	return s
 }
 
+func (s *NumberSchema) Step(step float64, base float64) *NumberSchema {
+	s = s.derive()
+	s.step = &step
+	s.stepBase = base
+	return s
+}
+
+func (s *NumberSchema) Digits(count int) *NumberSchema {
+	s = s.derive()
+	s.minDigits = &count
+	s.maxDigits = &count
+	return s
+}
+
+func (s *NumberSchema) MinDigits(count int) *NumberSchema {
+	s = s.derive()
+	s.minDigits = &count
+	return s
+}
+
+func (s *NumberSchema) MaxDigits(count int) *NumberSchema {
+	s = s.derive()
+	s.maxDigits = &count
+	return s
+}
+
 func (s *NumberSchema) Optional() Schema {
+	s = s.derive()
 	s.BaseSchema.setOptional()
 	return s
 }
 
 func (s *NumberSchema) Required() Schema {
+	s = s.derive()
 	s.BaseSchema.setRequired()
 	return s
 }
 
 func (s *NumberSchema) Default(value interface{}) Schema {
+	s = s.derive()
 	s.BaseSchema.setDefault(value)
 	return s
 }
 
+func (s *NumberSchema) DefaultFunc(fn func() interface{}) Schema {
+	s = s.derive()
+	s.BaseSchema.setDefaultFunc(fn)
+	return s
+}
+
+func (s *NumberSchema) Readonly() *NumberSchema {
+	s = s.derive()
+	s.BaseSchema.setReadonly()
+	return s
+}
+
+func (s *NumberSchema) WithMessage(code, message string) *NumberSchema {
+	s = s.derive()
+	s.BaseSchema.setMessage(code, message)
+	return s
+}
+
 func (s *NumberSchema) Validate(value interface{}) ValidationResult {
+	return s.validate(value, newContext())
+}
+
+func (s *NumberSchema) validate(value interface{}, ctx *validationContext) ValidationResult {
 	processedValue, shouldReturn, result := s.handleNil(value)
 	if shouldReturn {
 		return result
 	}
 
 	num, ok := convertToFloat64(processedValue)
+	var whole wholeNumber
+	var isWhole bool
+	if s.int {
+		whole, isWhole = exactInteger(processedValue)
+	}
+	if !ok && (s.coerce || ctx.coerce) {
+		num, ok = coerceToFloat64(processedValue)
+	}
 	if !ok {
 		return ValidationResult{
 			Valid:  false,
-			Errors: []ValidationError{{Message: "expected number", Code: "invalid_type", Value: value}},
+			Errors: s.applyMessages([]ValidationError{{Message: "expected number", Code: "invalid_type", Value: value, Params: map[string]interface{}{"expected": "number"}}}),
 		}
 	}
 
 	var errors []ValidationError
 
-	if s.int && !isInteger(num) {
+	if s.int && !isWhole && !isInteger(num) {
 		errors = append(errors, ValidationError{
 			Message: "expected integer",
 			Code:    "invalid_type",
 			Value:   num,
+			Params:  map[string]interface{}{"expected": "integer"},
+		})
+	}
+
+	if s.intKind != "" {
+		var tooSmall, tooBig bool
+		if isWhole {
+			tooSmall, tooBig = whole.outOfRange(s.intKind)
+		} else {
+			lower, upper := integerBounds(s.intKind)
+			tooSmall, tooBig = num < lower, num >= upper
+		}
+		if tooSmall {
+			errors = append(errors, ValidationError{
+				Message: fmt.Sprintf("number is too small for %s", s.intKind),
+				Code:    "too_small",
+				Value:   num,
+				Params:  map[string]interface{}{"min": integerLimits[s.intKind][0], "type": s.intKind},
+			})
+		} else if tooBig {
+			errors = append(errors, ValidationError{
+				Message: fmt.Sprintf("number is too big for %s", s.intKind),
+				Code:    "too_big",
+				Value:   num,
+				Params:  map[string]interface{}{"max": integerLimits[s.intKind][1], "type": s.intKind},
+			})
+		}
+	}
+
+	if s.min != nil && s.minExclusive && num <= *s.min {
+		errors = append(errors, ValidationError{
+			Message: fmt.Sprintf("number must be greater than %g", *s.min),
+			Code:    "too_small",
+			Value:   num,
+			Params:  map[string]interface{}{"min": *s.min, "inclusive": false},
 		})
 	}
 
-	if s.min != nil && num < *s.min {
+	// Integer schemas clamp to the nearest integer inside fractional bounds.
+	if s.clamp && s.min != nil && !s.minExclusive && num < *s.min {
+		num = *s.min
+		if s.int {
+			num = math.Ceil(num)
+		}
+	}
+	if s.clamp && s.max != nil && !s.maxExclusive && num > *s.max {
+		num = *s.max
+		if s.int {
+			num = math.Floor(num)
+		}
+	}
+
+	if s.min != nil && !s.minExclusive && num < *s.min {
+		message := fmt.Sprintf("number must be greater than or equal to %g", *s.min)
+		if s.between {
+			message = fmt.Sprintf("number must be between %g and %g", *s.min, *s.max)
+		}
 		errors = append(errors, ValidationError{
-			Message: fmt.Sprintf("number must be greater than or equal to %g", *s.min),
+			Message: message,
 			Code:    "too_small",
 			Value:   num,
+			Params:  s.rangeParams(map[string]interface{}{"min": *s.min, "inclusive": true}),
 		})
 	}
 
-	if s.max != nil && num > *s.max {
+	if s.max != nil && s.maxExclusive && num >= *s.max {
 		errors = append(errors, ValidationError{
-			Message: fmt.Sprintf("number must be less than or equal to %g", *s.max),
+			Message: fmt.Sprintf("number must be less than %g", *s.max),
 			Code:    "too_big",
 			Value:   num,
+			Params:  map[string]interface{}{"max": *s.max, "inclusive": false},
+		})
+	}
+
+	if s.max != nil && !s.maxExclusive && num > *s.max {
+		message := fmt.Sprintf("number must be less than or equal to %g", *s.max)
+		if s.between {
+			message = fmt.Sprintf("number must be between %g and %g", *s.min, *s.max)
+		}
+		errors = append(errors, ValidationError{
+			Message: message,
+			Code:    "too_big",
+			Value:   num,
+			Params:  s.rangeParams(map[string]interface{}{"max": *s.max, "inclusive": true}),
 		})
 	}
 
@@ -146,6 +368,7 @@ func (s *NumberSchema) Validate(value interface{}) ValidationResult {
 			Message: "number must be positive",
 			Code:    "too_small",
 			Value:   num,
+			Params:  map[string]interface{}{"min": 0, "inclusive": false},
 		})
 	}
 
@@ -154,6 +377,7 @@ func (s *NumberSchema) Validate(value interface{}) ValidationResult {
 			Message: "number must be negative",
 			Code:    "too_big",
 			Value:   num,
+			Params:  map[string]interface{}{"max": 0, "inclusive": false},
 		})
 	}
 
@@ -162,6 +386,7 @@ func (s *NumberSchema) Validate(value interface{}) ValidationResult {
 			Message: "number must be non-negative",
 			Code:    "too_small",
 			Value:   num,
+			Params:  map[string]interface{}{"min": 0},
 		})
 	}
 
@@ -170,6 +395,7 @@ func (s *NumberSchema) Validate(value interface{}) ValidationResult {
 			Message: "number must be non-positive",
 			Code:    "too_big",
 			Value:   num,
+			Params:  map[string]interface{}{"max": 0},
 		})
 	}
 
@@ -178,6 +404,7 @@ func (s *NumberSchema) Validate(value interface{}) ValidationResult {
 			Message: "number must be finite",
 			Code:    "invalid_type",
 			Value:   num,
+			Params:  map[string]interface{}{"expected": "finite"},
 		})
 	}
 
@@ -186,29 +413,112 @@ func (s *NumberSchema) Validate(value interface{}) ValidationResult {
 			Message: "number must be a safe integer",
 			Code:    "too_big",
 			Value:   num,
+			Params:  map[string]interface{}{"max": 9007199254740991},
 		})
 	}
 
-	if s.multipleOf != nil && math.Mod(num, *s.multipleOf) != 0 {
+	if s.multipleOf != nil && !isMultipleOf(num, *s.multipleOf) {
 		errors = append(errors, ValidationError{
 			Message: fmt.Sprintf("number must be a multiple of %g", *s.multipleOf),
-			Code:    "invalid_type",
+			Code:    "not_multiple_of",
 			Value:   num,
+			Params:  map[string]interface{}{"multipleOf": *s.multipleOf},
 		})
 	}
 
+	if s.step != nil && !isMultipleOf(num-s.stepBase, *s.step) {
+		errors = append(errors, ValidationError{
+			Message: fmt.Sprintf("number must be in steps of %g from %g", *s.step, s.stepBase),
+			Code:    "not_multiple_of",
+			Value:   num,
+			Params:  map[string]interface{}{"step": *s.step, "base": s.stepBase},
+		})
+	}
+
+	if s.minDigits != nil || s.maxDigits != nil {
+		digits := countDigits(num)
+		exact := s.minDigits != nil && s.maxDigits != nil && *s.minDigits == *s.maxDigits
+
+		if s.minDigits != nil && digits < *s.minDigits {
+			message := fmt.Sprintf("number must have at least %d digits", *s.minDigits)
+			if exact {
+				message = fmt.Sprintf("number must have %d digits", *s.minDigits)
+			}
+			errors = append(errors, ValidationError{
+				Message: message,
+				Code:    "too_small",
+				Value:   num,
+				Params:  map[string]interface{}{"minDigits": *s.minDigits},
+			})
+		}
+
+		if s.maxDigits != nil && digits > *s.maxDigits {
+			message := fmt.Sprintf("number must have at most %d digits", *s.maxDigits)
+			if exact {
+				message = fmt.Sprintf("number must have %d digits", *s.maxDigits)
+			}
+			errors = append(errors, ValidationError{
+				Message: message,
+				Code:    "too_big",
+				Value:   num,
+				Params:  map[string]interface{}{"maxDigits": *s.maxDigits},
+			})
+		}
+	}
+
 	if len(errors) > 0 {
-		return ValidationResult{Valid: false, Errors: errors}
+		return ValidationResult{Valid: false, Errors: s.applyMessages(errors)}
+	}
+
+	if isWhole && whole.float() != num {
+		isWhole = false // clamped
+	}
+
+	switch s.intKind {
+	case "int32":
+		return ValidationResult{Valid: true, Value: int32(num)}
+	case "uint":
+		if isWhole && whole.large {
+			return ValidationResult{Valid: true, Value: whole.unsigned}
+		}
+		if isWhole {
+			return ValidationResult{Valid: true, Value: uint64(whole.value)}
+		}
+		return ValidationResult{Valid: true, Value: uint64(num)}
 	}
 
 	if s.int {
+		if isWhole && !whole.large {
+			return ValidationResult{Valid: true, Value: whole.value}
+		}
 		return ValidationResult{Valid: true, Value: int64(num)}
 	}
 
 	return ValidationResult{Valid: true, Value: num}
 }
 
+// rangeParams adds the other bound to a Between error, so both min and max
+// are available to custom messages.
+func (s *NumberSchema) rangeParams(params map[string]interface{}) map[string]interface{} {
+	if s.between {
+		params["min"] = *s.min
+		params["max"] = *s.max
+	}
+	return params
+}
+
 func convertToFloat64(value interface{}) (float64, bool) {
+	switch n := value.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
 	v := reflect.ValueOf(value)
 	switch v.Kind() {
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
@@ -217,18 +527,275 @@ func convertToFloat64(value interface{}) (float64, bool) {
 		return float64(v.Uint()), true
 	case reflect.Float32, reflect.Float64:
 		return v.Float(), true
+	}
+	return 0, false
+}
+
+// wholeNumber is an integer input kept exact, since float64 rounds integers
+// beyond 2^53. Values above MaxInt64 are held in unsigned with large set.
+type wholeNumber struct {
+	value    int64
+	unsigned uint64
+	large    bool
+}
+
+// exactInteger returns the value of a Go integer, or of a json.Number holding
+// an integer as decoded by json.Decoder.UseNumber.
+func exactInteger(value interface{}) (wholeNumber, bool) {
+	if n, ok := value.(json.Number); ok {
+		if i, err := n.Int64(); err == nil {
+			return wholeNumber{value: i}, true
+		}
+		if u, err := strconv.ParseUint(string(n), 10, 64); err == nil {
+			return wholeNumber{unsigned: u, large: true}, true
+		}
+		return wholeNumber{}, false
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return wholeNumber{value: v.Int()}, true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if u := v.Uint(); u > math.MaxInt64 {
+			return wholeNumber{unsigned: u, large: true}, true
+		}
+		return wholeNumber{value: int64(v.Uint())}, true
+	}
+	return wholeNumber{}, false
+}
+
+func (n wholeNumber) float() float64 {
+	if n.large {
+		return float64(n.unsigned)
+	}
+	return float64(n.value)
+}
+
+// outOfRange compares n with the bounds of an integer kind exactly.
+func (n wholeNumber) outOfRange(kind string) (tooSmall, tooBig bool) {
+	switch kind {
+	case "int32":
+		return !n.large && n.value < math.MinInt32, n.large || n.value > math.MaxInt32
+	case "uint":
+		return !n.large && n.value < 0, false
+	}
+	return false, n.large
+}
+
+func coerceToFloat64(value interface{}) (float64, bool) {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
 	case reflect.String:
-		if f, err := parseFloat(v.String()); err == nil {
+		if f, err := parseFloat(strings.TrimSpace(v.String())); err == nil {
 			return f, true
 		}
+	case reflect.Bool:
+		if v.Bool() {
+			return 1, true
+		}
+		return 0, true
 	}
 	return 0, false
 }
 
+// countDigits counts the decimal digits of the integer part of num, ignoring
+// the sign, so -123456 has 6 digits.
+func countDigits(num float64) int {
+	return len(strconv.FormatFloat(math.Abs(math.Trunc(num)), 'f', 0, 64))
+}
+
+var integerLimits = map[string][2]interface{}{
+	"int32": {int32(math.MinInt32), int32(math.MaxInt32)},
+	"int64": {int64(math.MinInt64), int64(math.MaxInt64)},
+	"uint":  {uint64(0), uint64(math.MaxUint64)},
+}
+
+// integerBounds returns the inclusive lower and exclusive upper bound of an
+// integer kind. The upper bound is exclusive because 2^63 and 2^64 are exact
+// in float64 while the largest int64 and uint64 values are not.
+func integerBounds(kind string) (float64, float64) {
+	switch kind {
+	case "int32":
+		return math.MinInt32, math.MaxInt32 + 1
+	case "uint":
+		return 0, 1 << 64
+	}
+	return math.MinInt64, 1 << 63
+}
+
+const multipleOfEpsilon = 1e-9
+
+// isMultipleOf reports whether num is a whole multiple of step. When both
+// values have short decimal representations they are scaled to integers and
+// compared exactly, so 0.3 is a multiple of 0.1 but 0.35 is not. Otherwise
+// the quotient is compared against the nearest integer with a tolerance.
+func isMultipleOf(num, step float64) bool {
+	if step == 0 || math.IsInf(num, 0) || math.IsNaN(num) {
+		return false
+	}
+
+	places := decimalPlaces(step)
+	if p := decimalPlaces(num); p > places {
+		places = p
+	}
+	if places <= 15 {
+		scale := math.Pow10(places)
+		scaledNum, scaledStep := math.Round(num*scale), math.Round(step*scale)
+		if math.Abs(scaledNum) < 1<<53 && math.Abs(scaledStep) < 1<<53 && scaledStep != 0 {
+			return int64(scaledNum)%int64(scaledStep) == 0
+		}
+	}
+
+	quotient := num / step
+	return math.Abs(quotient-math.Round(quotient)) < multipleOfEpsilon
+}
+
+func decimalPlaces(num float64) int {
+	str := strconv.FormatFloat(math.Abs(num), 'f', -1, 64)
+	if idx := strings.IndexByte(str, '.'); idx != -1 {
+		return len(str) - idx - 1
+	}
+	return 0
+}
+
 func isInteger(num float64) bool {
 	return num == math.Trunc(num)
 }
 
 func parseFloat(s string) (float64, error) {
 	return strconv.ParseFloat(s, 64)
-}
\ No newline at end of file
+}
+
+type BigIntSchema struct {
+	BaseSchema
+	min      *big.Int
+	max      *big.Int
+	positive bool
+}
+
+func BigInt() *BigIntSchema {
+	return &BigIntSchema{
+		BaseSchema: BaseSchema{isRequired: true},
+	}
+}
+
+func (s *BigIntSchema) Min(value *big.Int) *BigIntSchema {
+	s.min = new(big.Int).Set(value)
+	return s
+}
+
+func (s *BigIntSchema) Max(value *big.Int) *BigIntSchema {
+	s.max = new(big.Int).Set(value)
+	return s
+}
+
+func (s *BigIntSchema) Positive() *BigIntSchema {
+	s.positive = true
+	return s
+}
+
+func (s *BigIntSchema) Optional() Schema {
+	s.BaseSchema.setOptional()
+	return s
+}
+
+func (s *BigIntSchema) Required() Schema {
+	s.BaseSchema.setRequired()
+	return s
+}
+
+func (s *BigIntSchema) Default(value interface{}) Schema {
+	s.BaseSchema.setDefault(value)
+	return s
+}
+
+func (s *BigIntSchema) DefaultFunc(fn func() interface{}) Schema {
+	s.BaseSchema.setDefaultFunc(fn)
+	return s
+}
+
+func (s *BigIntSchema) Readonly() *BigIntSchema {
+	s.BaseSchema.setReadonly()
+	return s
+}
+
+func (s *BigIntSchema) WithMessage(code, message string) *BigIntSchema {
+	s.BaseSchema.setMessage(code, message)
+	return s
+}
+
+func (s *BigIntSchema) Validate(value interface{}) ValidationResult {
+	processedValue, shouldReturn, result := s.handleNil(value)
+	if shouldReturn {
+		return result
+	}
+
+	num, ok := convertToBigInt(processedValue)
+	if !ok {
+		return ValidationResult{
+			Valid:  false,
+			Errors: s.applyMessages([]ValidationError{{Message: "expected integer", Code: "invalid_type", Value: value, Params: map[string]interface{}{"expected": "integer"}}}),
+		}
+	}
+
+	var errors []ValidationError
+
+	if s.min != nil && num.Cmp(s.min) < 0 {
+		errors = append(errors, ValidationError{
+			Message: fmt.Sprintf("number must be greater than or equal to %s", s.min),
+			Code:    "too_small",
+			Value:   num,
+			Params:  map[string]interface{}{"min": s.min},
+		})
+	}
+
+	if s.max != nil && num.Cmp(s.max) > 0 {
+		errors = append(errors, ValidationError{
+			Message: fmt.Sprintf("number must be less than or equal to %s", s.max),
+			Code:    "too_big",
+			Value:   num,
+			Params:  map[string]interface{}{"max": s.max},
+		})
+	}
+
+	if s.positive && num.Sign() <= 0 {
+		errors = append(errors, ValidationError{
+			Message: "number must be positive",
+			Code:    "too_small",
+			Value:   num,
+		})
+	}
+
+	if len(errors) > 0 {
+		return ValidationResult{Valid: false, Errors: s.applyMessages(errors)}
+	}
+
+	return ValidationResult{Valid: true, Value: num}
+}
+
+// convertToBigInt never goes through float64, so values beyond 2^53 keep
+// every digit. The result is always a fresh copy the caller may mutate.
+func convertToBigInt(value interface{}) (*big.Int, bool) {
+	switch v := value.(type) {
+	case *big.Int:
+		if v == nil {
+			return nil, false
+		}
+		return new(big.Int).Set(v), true
+	case big.Int:
+		return new(big.Int).Set(&v), true
+	case string:
+		return new(big.Int).SetString(strings.TrimSpace(v), 10)
+	case json.Number:
+		return new(big.Int).SetString(string(v), 10)
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return big.NewInt(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return new(big.Int).SetUint64(rv.Uint()), true
+	}
+	return nil, false
+}
@@ -1,26 +1,64 @@
 package god
 
 import (
+	"cmp"
 	"fmt"
+	"maps"
 	"reflect"
+	"slices"
+	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// UnknownKeys is how an object treats input keys that are not in its shape.
+type UnknownKeys int
+
+const (
+	// UnknownKeysStrip leaves unknown keys out of the output. It is the
+	// default.
+	UnknownKeysStrip UnknownKeys = iota
+	// UnknownKeysStrict reports each unknown key as an unrecognized_keys
+	// error.
+	UnknownKeysStrict
+	// UnknownKeysPassthrough copies unknown keys to the output unchanged.
+	UnknownKeysPassthrough
 )
 
 type ObjectSchema struct {
 	BaseSchema
-	fields        map[string]Schema
-	strict        bool
-	passthrough   bool
-	catchall      Schema
-	shape         map[string]Schema
-	keyof         []string
-	partial       bool
-	deepPartial   bool
-	required      []string
-	pick          []string
-	omit          []string
-	extend        map[string]Schema
-	merge         *ObjectSchema
+	fields       map[string]Schema
+	unknownKeys  UnknownKeys
+	catchall     Schema
+	shape        map[string]Schema
+	keyof        []string
+	partial      bool
+	deepPartial  bool
+	required     []string
+	pick         []string
+	omit         []string
+	extend       map[string]Schema
+	merges       []*ObjectSchema
+	rename       map[string]string
+	keyTransform func(string) string
+	groupUnknown bool
+	refinements  []func(obj map[string]interface{}) []ValidationError
+	cache        *fieldCache
+}
+
+// fieldCache holds the effective fields computed from an ObjectSchema's
+// modifiers, so they are not rebuilt on every validation. Modifiers return a
+// copy of the schema with an empty cache, so a cache is filled at most once
+// and concurrent validations may share it.
+type fieldCache struct {
+	mu      sync.Mutex
+	current atomic.Pointer[effectiveFields]
+}
+
+type effectiveFields struct {
+	fields map[string]Schema
+	keys   []string
 }
 
 func Object(fields map[string]Schema) *ObjectSchema {
@@ -28,52 +66,80 @@ func Object(fields map[string]Schema) *ObjectSchema {
 		BaseSchema: BaseSchema{isRequired: true},
 		fields:     fields,
 		shape:      fields,
+		cache:      &fieldCache{},
 	}
 }
 
+// Strict, Strip and Passthrough select how unknown keys are handled. They
+// are mutually exclusive; the last one called wins.
 func (s *ObjectSchema) Strict() *ObjectSchema {
-	s.strict = true
-	s.passthrough = false
+	s = s.derive()
+	s.unknownKeys = UnknownKeysStrict
+	return s
+}
+
+func (s *ObjectSchema) Strip() *ObjectSchema {
+	s = s.derive()
+	s.unknownKeys = UnknownKeysStrip
 	return s
 }
 
 func (s *ObjectSchema) Passthrough() *ObjectSchema {
-	s.passthrough = true
-	s.strict = false
+	s = s.derive()
+	s.unknownKeys = UnknownKeysPassthrough
+	return s
+}
+
+func (s *ObjectSchema) UnknownKeysMode() UnknownKeys {
+	return s.unknownKeys
+}
+
+// GroupUnknownKeys makes Strict report every unknown key in a single
+// unrecognized_keys error instead of one error per key.
+func (s *ObjectSchema) GroupUnknownKeys() *ObjectSchema {
+	s = s.derive()
+	s.groupUnknown = true
 	return s
 }
 
 func (s *ObjectSchema) Catchall(schema Schema) *ObjectSchema {
+	s = s.derive()
 	s.catchall = schema
 	return s
 }
 
 func (s *ObjectSchema) Partial() *ObjectSchema {
+	s = s.derive()
 	s.partial = true
 	return s
 }
 
 func (s *ObjectSchema) DeepPartial() *ObjectSchema {
+	s = s.derive()
 	s.deepPartial = true
 	return s
 }
 
 func (s *ObjectSchema) RequiredFields(fields ...string) *ObjectSchema {
+	s = s.derive()
 	s.required = append(s.required, fields...)
 	return s
 }
 
 func (s *ObjectSchema) Pick(fields ...string) *ObjectSchema {
+	s = s.derive()
 	s.pick = fields
 	return s
 }
 
 func (s *ObjectSchema) Omit(fields ...string) *ObjectSchema {
+	s = s.derive()
 	s.omit = fields
 	return s
 }
 
 func (s *ObjectSchema) Extend(fields map[string]Schema) *ObjectSchema {
+	s = s.derive()
 	if s.extend == nil {
 		s.extend = make(map[string]Schema)
 	}
@@ -83,56 +149,232 @@ func (s *ObjectSchema) Extend(fields map[string]Schema) *ObjectSchema {
 	return s
 }
 
+// ExtendStrict is Extend for overriding existing fields only. It returns an
+// error naming any key that is not already a field, so a misspelled key is
+// caught when the schema is built.
+func (s *ObjectSchema) ExtendStrict(fields map[string]Schema) (*ObjectSchema, error) {
+	if err := s.checkFieldNames("extend", sortedKeys(fields)); err != nil {
+		return s, err
+	}
+	return s.Extend(fields), nil
+}
+
+// PickStrict is Pick that returns an error when a name is not a field.
+func (s *ObjectSchema) PickStrict(fields ...string) (*ObjectSchema, error) {
+	if err := s.checkFieldNames("pick", fields); err != nil {
+		return s, err
+	}
+	return s.Pick(fields...), nil
+}
+
+// OmitStrict is Omit that returns an error when a name is not a field.
+func (s *ObjectSchema) OmitStrict(fields ...string) (*ObjectSchema, error) {
+	if err := s.checkFieldNames("omit", fields); err != nil {
+		return s, err
+	}
+	return s.Omit(fields...), nil
+}
+
+func (s *ObjectSchema) checkFieldNames(operation string, names []string) error {
+	existing := s.getEffectiveFields()
+	var unknown []string
+	for _, name := range names {
+		if _, exists := existing[name]; !exists {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		return fmt.Errorf("%s: unknown fields %s", operation, strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// Merge adds other's fields, after its own Pick, Omit, Extend and Partial,
+// overriding fields of the same name. Other modifiers combine as follows:
+//   - unknown keys use the more restrictive mode: Strict, then Strip, then
+//     Passthrough. Calling Strict, Strip or Passthrough later still wins;
+//   - Catchall, KeyTransform and renamed keys from this schema win over
+//     other's;
+//   - RequiredFields, Refine checks and GroupUnknownKeys from both apply.
+//
+// Optional and Default settings of the merged object itself are this
+// schema's.
 func (s *ObjectSchema) Merge(other *ObjectSchema) *ObjectSchema {
-	s.merge = other
+	s = s.derive()
+	s.merges = append(s.merges, other)
+
+	if unknownKeysRank(other.unknownKeys) > unknownKeysRank(s.unknownKeys) {
+		s.unknownKeys = other.unknownKeys
+	}
+	if s.catchall == nil {
+		s.catchall = other.catchall
+	}
+	if s.keyTransform == nil {
+		s.keyTransform = other.keyTransform
+	}
+	for from, to := range other.rename {
+		if _, exists := s.rename[from]; !exists {
+			if s.rename == nil {
+				s.rename = make(map[string]string)
+			}
+			s.rename[from] = to
+		}
+	}
+	s.groupUnknown = s.groupUnknown || other.groupUnknown
+	s.required = append(s.required, other.required...)
+	s.refinements = append(s.refinements, other.refinements...)
+
 	return s
 }
 
-func (s *ObjectSchema) Keyof() []string {
-	var keys []string
-	for key := range s.getEffectiveFields() {
-		keys = append(keys, key)
+// unknownKeysRank orders unknown key modes from most permissive to most
+// restrictive.
+func unknownKeysRank(mode UnknownKeys) int {
+	switch mode {
+	case UnknownKeysPassthrough:
+		return 0
+	case UnknownKeysStrip:
+		return 1
 	}
-	return keys
+	return 2
+}
+
+// Rename maps input keys to schema keys before validation, so
+// Rename(map[string]string{"first_name": "firstName"}) validates first_name
+// against the firstName field and returns it as firstName.
+func (s *ObjectSchema) Rename(keys map[string]string) *ObjectSchema {
+	s = s.derive()
+	if s.rename == nil {
+		s.rename = make(map[string]string)
+	}
+	for from, to := range keys {
+		s.rename[from] = to
+	}
+	return s
+}
+
+// KeyTransform rewrites every incoming key before it is matched against the
+// fields. It runs before Rename, so Rename sees the transformed keys.
+func (s *ObjectSchema) KeyTransform(fn func(string) string) *ObjectSchema {
+	s = s.derive()
+	s.keyTransform = fn
+	return s
+}
+
+// Refine adds a cross-field check. It runs only once every field has passed
+// and receives the validated object. Returned errors keep their Field and
+// Path; a Field without a Path is treated as a single key.
+func (s *ObjectSchema) Refine(fn func(obj map[string]interface{}) []ValidationError) *ObjectSchema {
+	s = s.derive()
+	s.refinements = append(s.refinements, fn)
+	return s
+}
+
+func (s *ObjectSchema) Shape() map[string]Schema {
+	return maps.Clone(s.getEffectiveFields())
+}
+
+func (s *ObjectSchema) Field(name string) (Schema, bool) {
+	schema, exists := s.getEffectiveFields()[name]
+	return schema, exists
+}
+
+func (s *ObjectSchema) Keyof() []string {
+	return slices.Clone(s.effectiveFields().keys)
 }
 
 func (s *ObjectSchema) Optional() Schema {
+	s = s.derive()
 	s.BaseSchema.setOptional()
 	return s
 }
 
 func (s *ObjectSchema) Required() Schema {
+	s = s.derive()
 	s.BaseSchema.setRequired()
 	return s
 }
 
 func (s *ObjectSchema) Default(value interface{}) Schema {
+	s = s.derive()
 	s.BaseSchema.setDefault(value)
 	return s
 }
 
+func (s *ObjectSchema) DefaultFunc(fn func() interface{}) Schema {
+	s = s.derive()
+	s.BaseSchema.setDefaultFunc(fn)
+	return s
+}
+
+func (s *ObjectSchema) Readonly() *ObjectSchema {
+	s = s.derive()
+	s.BaseSchema.setReadonly()
+	return s
+}
+
+func (s *ObjectSchema) WithMessage(code, message string) *ObjectSchema {
+	s = s.derive()
+	s.BaseSchema.setMessage(code, message)
+	return s
+}
+
+// getEffectiveFields returns the cached effective fields. The map is shared,
+// so callers must not modify it.
 func (s *ObjectSchema) getEffectiveFields() map[string]Schema {
+	return s.effectiveFields().fields
+}
+
+func (s *ObjectSchema) effectiveFields() *effectiveFields {
+	if s.cache == nil {
+		fields := s.computeEffectiveFields()
+		return &effectiveFields{fields: fields, keys: sortedKeys(fields)}
+	}
+	if cached := s.cache.current.Load(); cached != nil {
+		return cached
+	}
+
+	// Only one goroutine computes the fields, so concurrent callers share
+	// the same result.
+	s.cache.mu.Lock()
+	defer s.cache.mu.Unlock()
+	if cached := s.cache.current.Load(); cached != nil {
+		return cached
+	}
+	fields := s.computeEffectiveFields()
+	computed := &effectiveFields{fields: fields, keys: sortedKeys(fields)}
+	s.cache.current.Store(computed)
+	return computed
+}
+
+// computeEffectiveFields applies the modifiers in a fixed order, whatever
+// order they were called in: base fields, then Merge, Extend, Pick, Omit,
+// Partial or DeepPartial, and finally RequiredFields. Later steps win, so
+// Extend overrides a merged field and RequiredFields overrides Partial.
+// Partial and RequiredFields only see the fields left after Pick and Omit,
+// and they change copies of the field schemas, never the schemas passed in.
+func (s *ObjectSchema) computeEffectiveFields() map[string]Schema {
 	fields := make(map[string]Schema)
-	
+
 	// Start with base fields
 	for k, v := range s.fields {
 		fields[k] = v
 	}
-	
-	// Apply merge
-	if s.merge != nil {
-		for k, v := range s.merge.fields {
+
+	// Apply merges in the order they were added
+	for _, other := range s.merges {
+		for k, v := range other.getEffectiveFields() {
 			fields[k] = v
 		}
 	}
-	
+
 	// Apply extend
 	if s.extend != nil {
 		for k, v := range s.extend {
 			fields[k] = v
 		}
 	}
-	
+
 	// Apply pick
 	if len(s.pick) > 0 {
 		picked := make(map[string]Schema)
@@ -143,160 +385,819 @@ func (s *ObjectSchema) getEffectiveFields() map[string]Schema {
 		}
 		fields = picked
 	}
-	
+
 	// Apply omit
 	if len(s.omit) > 0 {
 		for _, key := range s.omit {
 			delete(fields, key)
 		}
 	}
-	
+
 	// Apply partial
 	if s.partial || s.deepPartial {
 		for k, v := range fields {
-			fields[k] = v.Optional()
+			if s.deepPartial {
+				v = deepPartialSchema(v)
+			}
+			fields[k] = cloneSchema(v).Optional()
 		}
 	}
-	
+
 	// Apply required
 	if len(s.required) > 0 {
 		for _, key := range s.required {
 			if schema, exists := fields[key]; exists {
-				fields[key] = schema.Required()
+				fields[key] = cloneSchema(schema).Required()
 			}
 		}
 	}
-	
+
 	return fields
 }
 
+// rejectsNull reports whether an object field may not be present with a null
+// value, so "you sent null" can be reported apart from "you left it out".
+// Nullable and Nullish fields accept null, and a Default replaces it.
+// Schemas from outside this package receive nil as before.
+func rejectsNull(schema Schema) bool {
+	switch s := schema.(type) {
+	case *NullableSchema, *CatchSchema:
+		return false
+	case *TransformSchema:
+		return rejectsNull(s.schema)
+	case *PreprocessSchema:
+		return !s.includeNil && rejectsNull(s.schema)
+	case *PipeSchema:
+		return rejectsNull(s.from)
+	case *BrandSchema:
+		return rejectsNull(s.schema)
+	case interface{ acceptsNull() bool }:
+		return !s.acceptsNull()
+	}
+	return false
+}
+
+// requiresKey reports whether schema needs an object field to be present
+// even though it accepts null, as Nullable does without Optional.
+func requiresKey(schema Schema) bool {
+	switch s := schema.(type) {
+	case *NullableSchema:
+		return !s.isOptional && !s.hasDefault
+	case *TransformSchema:
+		return requiresKey(s.schema)
+	case *PreprocessSchema:
+		return !s.includeNil && requiresKey(s.schema)
+	case *PipeSchema:
+		return requiresKey(s.from)
+	case *BrandSchema:
+		return requiresKey(s.schema)
+	}
+	return false
+}
+
+// cloneSchema returns a shallow copy of a schema so Optional and Required
+// can be applied without changing a schema that other objects share.
+// Schemas that are not pointers to structs are returned unchanged.
+func cloneSchema(schema Schema) Schema {
+	v := reflect.ValueOf(schema)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return schema
+	}
+	clone := reflect.New(v.Elem().Type())
+	clone.Elem().Set(v.Elem())
+	switch c := clone.Interface().(type) {
+	case *ObjectSchema:
+		c.cache = &fieldCache{}
+	case *LazySchema:
+		c.once = sync.Once{}
+		c.cached = nil
+		c.selfRef = false
+	}
+	return clone.Interface().(Schema)
+}
+
+func deepPartialSchema(schema Schema) Schema {
+	switch v := schema.(type) {
+	case *ObjectSchema:
+		clone := *v
+		clone.deepPartial = true
+		clone.cache = &fieldCache{}
+		return &clone
+	case *ArraySchema:
+		clone := *v
+		clone.element = deepPartialSchema(v.element)
+		return &clone
+	case *NullableSchema:
+		clone := *v
+		clone.schema = deepPartialSchema(v.schema)
+		return &clone
+	}
+	return schema
+}
+
 func (s *ObjectSchema) Validate(value interface{}) ValidationResult {
+	return s.validate(value, newContext())
+}
+
+func (s *ObjectSchema) validate(value interface{}, ctx *validationContext) ValidationResult {
 	processedValue, shouldReturn, result := s.handleNil(value)
 	if shouldReturn {
 		return result
 	}
 
-	// Check if value is a map or struct
-	v := reflect.ValueOf(processedValue)
-	if v.Kind() == reflect.Ptr {
-		v = v.Elem()
+	if result, ok := ctx.enter(); !ok {
+		return result
 	}
+	defer ctx.leave()
 
-	var objMap map[string]interface{}
-	var ok bool
-
-	switch v.Kind() {
-	case reflect.Map:
-		objMap, ok = convertMapToStringInterface(processedValue)
-		if !ok {
-			return ValidationResult{
-				Valid:  false,
-				Errors: []ValidationError{{Message: "expected object", Code: "invalid_type", Value: value}},
-			}
-		}
-	case reflect.Struct:
-		objMap = structToMap(v)
-	default:
+	// Check if value is a map or struct
+	objMap, ok := toObjectMap(processedValue, ctx.structTag)
+	if !ok {
 		return ValidationResult{
 			Valid:  false,
-			Errors: []ValidationError{{Message: "expected object", Code: "invalid_type", Value: value}},
+			Errors: s.applyMessages([]ValidationError{{Message: "expected object", Code: "invalid_type", Value: value, Params: map[string]interface{}{"expected": "object"}}}),
 		}
 	}
 
-	fields := s.getEffectiveFields()
-	var errors []ValidationError
-	validatedObj := make(map[string]interface{})
+	effective := s.effectiveFields()
+	fields := effective.fields
+	fromStruct := isStruct(processedValue)
+	errors, errorSlot := ctx.errorBuffer()
+	var warnings []ValidationError
+
+	if len(s.rename) > 0 || s.keyTransform != nil {
+		var keyErrors []ValidationError
+		objMap, keyErrors = s.normalizeKeys(objMap)
+		errors = append(errors, s.applyMessages(keyErrors)...)
+	}
+	validatedObj := ctx.objectBuffer(len(fields))
 
-	// Validate known fields
-	for fieldName, fieldSchema := range fields {
+	// Validate known fields in sorted order so errors are deterministic
+	for _, fieldName := range effective.keys {
+		if ctx.abortEarly && len(errors) > 0 {
+			break
+		}
+		fieldSchema := fields[fieldName]
 		fieldValue, exists := objMap[fieldName]
-		if !exists {
+		if isNil(fieldValue) {
 			fieldValue = nil
 		}
+		// A nil pointer field cannot tell an absent value from null, so it
+		// counts as absent unless the schema accepts null.
+		if fromStruct && exists && fieldValue == nil && rejectsNull(fieldSchema) {
+			exists = false
+		}
+
+		// Children see both an absent key and a null value as nil, so the
+		// presence rules of Optional and Nullable are enforced here.
+		if exists && fieldValue == nil && rejectsNull(fieldSchema) {
+			errors = append(errors, s.applyMessages([]ValidationError{{
+				Field:   fieldName,
+				Path:    []PathSegment{keySegment(fieldName)},
+				Message: "expected a value, received null",
+				Code:    "invalid_type",
+				Params:  map[string]interface{}{"expected": "non-null"},
+			}})...)
+			continue
+		}
+		if !exists && requiresKey(fieldSchema) {
+			errors = append(errors, s.applyMessages([]ValidationError{{
+				Field:   fieldName,
+				Path:    []PathSegment{keySegment(fieldName)},
+				Message: "field is required",
+				Code:    "required",
+			}})...)
+			continue
+		}
 
-		result := fieldSchema.Validate(fieldValue)
+		result := validateChild(fieldSchema, fieldValue, ctx)
+		for _, warning := range result.Warnings {
+			warning = warning.withPrefix(keySegment(fieldName))
+			warnings = append(warnings, warning)
+		}
 		if !result.Valid {
 			for _, err := range result.Errors {
-				err.Field = fieldName
+				err = err.withPrefix(keySegment(fieldName))
 				errors = append(errors, err)
 			}
-		} else {
-			if result.Value != nil {
-				validatedObj[fieldName] = result.Value
-			}
+		} else if exists || result.Value != nil {
+			// Every valid field that was sent or defaulted is kept, including
+			// zero values such as false, 0 and "". Only a missing optional
+			// field is left out.
+			validatedObj[fieldName] = result.Value
 		}
 	}
 
-	// Handle unknown fields
-	for fieldName, fieldValue := range objMap {
+	// Handle unknown fields. Stripped keys need no work, so the input keys are
+	// only sorted when unknown keys are kept, checked or rejected.
+	var unknownKeys []string
+	var inputKeys []string
+	if s.unknownKeys != UnknownKeysStrip || s.catchall != nil {
+		inputKeys = sortedKeys(objMap)
+	}
+	for _, fieldName := range inputKeys {
+		if ctx.abortEarly && len(errors) > 0 {
+			break
+		}
+		fieldValue := objMap[fieldName]
 		if _, exists := fields[fieldName]; !exists {
-			if s.strict {
-				errors = append(errors, ValidationError{
+			if s.unknownKeys == UnknownKeysStrict && s.groupUnknown {
+				unknownKeys = append(unknownKeys, fieldName)
+			} else if s.unknownKeys == UnknownKeysStrict {
+				errors = append(errors, s.applyMessages([]ValidationError{{
 					Field:   fieldName,
+					Path:    []PathSegment{keySegment(fieldName)},
 					Message: "unknown field",
 					Code:    "unrecognized_keys",
 					Value:   fieldValue,
-				})
+				}})...)
 			} else if s.catchall != nil {
-				result := s.catchall.Validate(fieldValue)
+				result := validateChild(s.catchall, fieldValue, ctx)
+				for _, warning := range result.Warnings {
+					warning = warning.withPrefix(keySegment(fieldName))
+					warnings = append(warnings, warning)
+				}
 				if !result.Valid {
 					for _, err := range result.Errors {
-						err.Field = fieldName
+						err = err.withPrefix(keySegment(fieldName))
 						errors = append(errors, err)
 					}
 				} else {
 					validatedObj[fieldName] = result.Value
 				}
-			} else if s.passthrough {
+			} else if s.unknownKeys == UnknownKeysPassthrough {
 				validatedObj[fieldName] = fieldValue
 			}
 		}
 	}
 
+	if len(unknownKeys) > 0 {
+		sort.Strings(unknownKeys)
+		errors = append(errors, s.applyMessages([]ValidationError{{
+			Message: fmt.Sprintf("unrecognized keys: %s", strings.Join(unknownKeys, ", ")),
+			Code:    "unrecognized_keys",
+			Value:   unknownKeys,
+			Params:  map[string]interface{}{"keys": unknownKeys},
+		}})...)
+	}
+
+	if len(errors) == 0 {
+		for _, refine := range s.refinements {
+			for _, err := range refine(validatedObj) {
+				if len(err.Path) == 0 && err.Field != "" {
+					err.Path = []PathSegment{keySegment(err.Field)}
+				}
+				if err.Code == "" {
+					err.Code = "custom"
+				}
+				errors = append(errors, s.applyMessages([]ValidationError{err})...)
+			}
+			if ctx.abortEarly && len(errors) > 0 {
+				break
+			}
+		}
+	}
+
+	if len(errors) > 0 {
+		return ValidationResult{Valid: false, Errors: ctx.keepErrors(errorSlot, errors), Warnings: warnings}
+	}
+
+	return ValidationResult{Valid: true, Value: validatedObj, Warnings: warnings}
+}
+
+type RecordSchema struct {
+	BaseSchema
+	key   Schema
+	value Schema
+}
+
+func Record(keySchema Schema, valueSchema Schema) *RecordSchema {
+	return &RecordSchema{
+		BaseSchema: BaseSchema{isRequired: true},
+		key:        keySchema,
+		value:      valueSchema,
+	}
+}
+
+func (s *RecordSchema) Optional() Schema {
+	s.BaseSchema.setOptional()
+	return s
+}
+
+func (s *RecordSchema) Required() Schema {
+	s.BaseSchema.setRequired()
+	return s
+}
+
+func (s *RecordSchema) Default(value interface{}) Schema {
+	s.BaseSchema.setDefault(value)
+	return s
+}
+
+func (s *RecordSchema) DefaultFunc(fn func() interface{}) Schema {
+	s.BaseSchema.setDefaultFunc(fn)
+	return s
+}
+
+func (s *RecordSchema) Readonly() *RecordSchema {
+	s.BaseSchema.setReadonly()
+	return s
+}
+
+func (s *RecordSchema) WithMessage(code, message string) *RecordSchema {
+	s.BaseSchema.setMessage(code, message)
+	return s
+}
+
+func (s *RecordSchema) Validate(value interface{}) ValidationResult {
+	return s.validate(value, newContext())
+}
+
+func (s *RecordSchema) validate(value interface{}, ctx *validationContext) ValidationResult {
+	processedValue, shouldReturn, result := s.handleNil(value)
+	if shouldReturn {
+		return result
+	}
+
+	if result, ok := ctx.enter(); !ok {
+		return result
+	}
+	defer ctx.leave()
+
+	objMap, ok := convertMapToStringInterface(processedValue)
+	if !ok {
+		return ValidationResult{
+			Valid:  false,
+			Errors: s.applyMessages([]ValidationError{{Message: "expected record", Code: "invalid_type", Value: value, Params: map[string]interface{}{"expected": "record"}}}),
+		}
+	}
+
+	errors, errorSlot := ctx.errorBuffer()
+	var warnings []ValidationError
+	validatedRecord := ctx.objectBuffer(len(objMap))
+
+	for _, key := range sortedKeys(objMap) {
+		if ctx.abortEarly && len(errors) > 0 {
+			break
+		}
+		fieldValue := objMap[key]
+
+		keyResult := validateChild(s.key, key, ctx)
+		if !keyResult.Valid {
+			for _, err := range keyResult.Errors {
+				err = err.withPrefix(keySegment(key))
+				errors = append(errors, err)
+			}
+			continue
+		}
+
+		// The key schema may transform the key, so it must not collide with
+		// another key after validation.
+		validatedKey := key
+		if str, ok := keyResult.Value.(string); ok {
+			validatedKey = str
+		}
+		if _, exists := validatedRecord[validatedKey]; exists {
+			errors = append(errors, s.applyMessages([]ValidationError{{
+				Field:   key,
+				Path:    []PathSegment{keySegment(key)},
+				Message: fmt.Sprintf("key %v collides with another key after validation", key),
+				Code:    "invalid_key",
+				Value:   key,
+			}})...)
+			continue
+		}
+
+		result := validateChild(s.value, fieldValue, ctx)
+		for _, warning := range result.Warnings {
+			warning = warning.withPrefix(keySegment(key))
+			warnings = append(warnings, warning)
+		}
+		if !result.Valid {
+			for _, err := range result.Errors {
+				err = err.withPrefix(keySegment(key))
+				errors = append(errors, err)
+			}
+		} else {
+			validatedRecord[validatedKey] = result.Value
+		}
+	}
+
+	if len(errors) > 0 {
+		return ValidationResult{Valid: false, Errors: ctx.keepErrors(errorSlot, errors), Warnings: warnings}
+	}
+
+	return ValidationResult{Valid: true, Value: validatedRecord, Warnings: warnings}
+}
+
+type MapSchema struct {
+	BaseSchema
+	key   Schema
+	value Schema
+}
+
+func Map(keySchema Schema, valueSchema Schema) *MapSchema {
+	return &MapSchema{
+		BaseSchema: BaseSchema{isRequired: true},
+		key:        keySchema,
+		value:      valueSchema,
+	}
+}
+
+func (s *MapSchema) Optional() Schema {
+	s.BaseSchema.setOptional()
+	return s
+}
+
+func (s *MapSchema) Required() Schema {
+	s.BaseSchema.setRequired()
+	return s
+}
+
+func (s *MapSchema) Default(value interface{}) Schema {
+	s.BaseSchema.setDefault(value)
+	return s
+}
+
+func (s *MapSchema) DefaultFunc(fn func() interface{}) Schema {
+	s.BaseSchema.setDefaultFunc(fn)
+	return s
+}
+
+func (s *MapSchema) Readonly() *MapSchema {
+	s.BaseSchema.setReadonly()
+	return s
+}
+
+func (s *MapSchema) WithMessage(code, message string) *MapSchema {
+	s.BaseSchema.setMessage(code, message)
+	return s
+}
+
+func (s *MapSchema) Validate(value interface{}) ValidationResult {
+	return s.validate(value, newContext())
+}
+
+// compareMapKeys orders map keys so errors come out in the same order on
+// every run. Numbers, strings and booleans compare by value; keys of other or
+// mixed types fall back to their type name and then their formatted value.
+func compareMapKeys(a, b interface{}) int {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	if va.IsValid() && vb.IsValid() && va.Type() == vb.Type() {
+		switch va.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return cmp.Compare(va.Int(), vb.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return cmp.Compare(va.Uint(), vb.Uint())
+		case reflect.Float32, reflect.Float64:
+			return cmp.Compare(va.Float(), vb.Float())
+		case reflect.String:
+			return cmp.Compare(va.String(), vb.String())
+		case reflect.Bool:
+			if va.Bool() == vb.Bool() {
+				return 0
+			}
+			if vb.Bool() {
+				return -1
+			}
+			return 1
+		}
+	}
+	if c := cmp.Compare(fmt.Sprintf("%T", a), fmt.Sprintf("%T", b)); c != 0 {
+		return c
+	}
+	return cmp.Compare(fmt.Sprintf("%v", a), fmt.Sprintf("%v", b))
+}
+
+func (s *MapSchema) validate(value interface{}, ctx *validationContext) ValidationResult {
+	processedValue, shouldReturn, result := s.handleNil(value)
+	if shouldReturn {
+		return result
+	}
+
+	if result, ok := ctx.enter(); !ok {
+		return result
+	}
+	defer ctx.leave()
+
+	v := reflect.ValueOf(processedValue)
+	if v.Kind() != reflect.Map {
+		return ValidationResult{
+			Valid:  false,
+			Errors: s.applyMessages([]ValidationError{{Message: "expected map", Code: "invalid_type", Value: value, Params: map[string]interface{}{"expected": "map"}}}),
+		}
+	}
+
+	var errors []ValidationError
+	var warnings []ValidationError
+	validatedMap := make(map[interface{}]interface{}, v.Len())
+
+	mapKeys := v.MapKeys()
+	sort.SliceStable(mapKeys, func(i, j int) bool {
+		return compareMapKeys(mapKeys[i].Interface(), mapKeys[j].Interface()) < 0
+	})
+
+	for _, mapKey := range mapKeys {
+		if ctx.abortEarly && len(errors) > 0 {
+			break
+		}
+		key := mapKey.Interface()
+		segment := keySegment(fmt.Sprintf("%v", key))
+
+		keyResult := validateChild(s.key, key, ctx)
+		if !keyResult.Valid {
+			for _, err := range keyResult.Errors {
+				err = err.withPrefix(segment)
+				errors = append(errors, err)
+			}
+			continue
+		}
+
+		// The key schema may coerce or transform the key, so the result has
+		// to be usable as a map key and must not collide with another key.
+		validatedKey := keyResult.Value
+		if validatedKey == nil || !reflect.TypeOf(validatedKey).Comparable() {
+			errors = append(errors, s.applyMessages([]ValidationError{{
+				Field:   segment.Key,
+				Path:    []PathSegment{segment},
+				Message: "validated key is not comparable",
+				Code:    "invalid_key",
+				Value:   key,
+			}})...)
+			continue
+		}
+		if _, exists := validatedMap[validatedKey]; exists {
+			errors = append(errors, s.applyMessages([]ValidationError{{
+				Field:   segment.Key,
+				Path:    []PathSegment{segment},
+				Message: fmt.Sprintf("key %v collides with another key after validation", key),
+				Code:    "invalid_key",
+				Value:   key,
+			}})...)
+			continue
+		}
+
+		result := validateChild(s.value, v.MapIndex(mapKey).Interface(), ctx)
+		for _, warning := range result.Warnings {
+			warning = warning.withPrefix(segment)
+			warnings = append(warnings, warning)
+		}
+		if !result.Valid {
+			for _, err := range result.Errors {
+				err = err.withPrefix(segment)
+				errors = append(errors, err)
+			}
+		} else {
+			validatedMap[validatedKey] = result.Value
+		}
+	}
+
 	if len(errors) > 0 {
-		return ValidationResult{Valid: false, Errors: errors}
+		return ValidationResult{Valid: false, Errors: errors, Warnings: warnings}
+	}
+
+	return ValidationResult{Valid: true, Value: validatedMap, Warnings: warnings}
+}
+
+func (s *ObjectSchema) normalizeKeys(objMap map[string]interface{}) (map[string]interface{}, []ValidationError) {
+	renamed := make(map[string]interface{}, len(objMap))
+	sources := make(map[string]string, len(objMap))
+	var errors []ValidationError
+
+	for _, key := range sortedKeys(objMap) {
+		target := key
+		if s.keyTransform != nil {
+			target = s.keyTransform(target)
+		}
+		if to, exists := s.rename[target]; exists {
+			target = to
+		}
+		if source, exists := sources[target]; exists {
+			errors = append(errors, ValidationError{
+				Field:   target,
+				Path:    []PathSegment{keySegment(target)},
+				Message: fmt.Sprintf("keys '%s' and '%s' both map to field '%s'", source, key, target),
+				Code:    "invalid_key",
+				Value:   objMap[key],
+			})
+			continue
+		}
+		sources[target] = key
+		renamed[target] = objMap[key]
 	}
 
-	return ValidationResult{Valid: true, Value: validatedObj}
+	return renamed, errors
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// toObjectMap reads maps and structs as objects. Struct fields are named by
+// structTag when set, then by their json tag, then by the Go field name.
+// map[string]interface{} inputs are returned as is, without copying, so
+// callers must not modify the result.
+func toObjectMap(value interface{}, structTag string) (map[string]interface{}, bool) {
+	if m, ok := value.(map[string]interface{}); ok {
+		return m, true
+	}
+	v := reflect.ValueOf(value)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Map:
+		return convertMapValue(v), true
+	case reflect.Struct:
+		return structToMap(v, structTag), true
+	}
+	return nil, false
+}
+
+// isStruct reports whether value is a struct or a pointer to one.
+func isStruct(value interface{}) bool {
+	v := reflect.ValueOf(value)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	return v.Kind() == reflect.Struct
 }
 
 func convertMapToStringInterface(value interface{}) (map[string]interface{}, bool) {
+	if m, ok := value.(map[string]interface{}); ok {
+		return m, true
+	}
 	v := reflect.ValueOf(value)
 	if v.Kind() != reflect.Map {
 		return nil, false
 	}
+	return convertMapValue(v), true
+}
 
-	result := make(map[string]interface{})
-	for _, key := range v.MapKeys() {
-		keyStr := fmt.Sprintf("%v", key.Interface())
-		result[keyStr] = v.MapIndex(key).Interface()
+func convertMapValue(v reflect.Value) map[string]interface{} {
+	result := make(map[string]interface{}, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		key := iter.Key()
+		var keyStr string
+		if key.Kind() == reflect.String {
+			keyStr = key.String()
+		} else {
+			keyStr = fmt.Sprintf("%v", key.Interface())
+		}
+		result[keyStr] = iter.Value().Interface()
 	}
-	return result, true
+	return result
 }
 
-func structToMap(v reflect.Value) map[string]interface{} {
-	result := make(map[string]interface{})
-	t := v.Type()
-
-	for i := 0; i < v.NumField(); i++ {
-		field := t.Field(i)
-		fieldValue := v.Field(i)
-
-		if !fieldValue.CanInterface() {
+// structToMap reads the exported fields of a struct the way encoding/json
+// does: fields of embedded structs are promoted unless the embedded field is
+// named by a tag, and fields tagged "-" are skipped.
+func structToMap(v reflect.Value, structTag string) map[string]interface{} {
+	fields := cachedStructFields(v.Type(), structTag)
+	result := make(map[string]interface{}, len(fields))
+	for _, field := range fields {
+		// A nil embedded pointer has no fields to read.
+		fieldValue, err := v.FieldByIndexErr(field.index)
+		if err != nil {
 			continue
 		}
+		result[field.name] = fieldValue.Interface()
+	}
+	return result
+}
+
+type structField struct {
+	name   string
+	index  []int
+	tagged bool
+}
 
-		fieldName := field.Name
-		if tag := field.Tag.Get("json"); tag != "" && tag != "-" {
-			if idx := strings.Index(tag, ","); idx != -1 {
-				fieldName = tag[:idx]
-			} else {
-				fieldName = tag
+type structFieldsKey struct {
+	t   reflect.Type
+	tag string
+}
+
+// structFieldsCache holds the fields of each struct type and tag, since
+// working them out walks every embedded struct.
+var structFieldsCache sync.Map
+
+func cachedStructFields(t reflect.Type, structTag string) []structField {
+	key := structFieldsKey{t: t, tag: structTag}
+	if fields, ok := structFieldsCache.Load(key); ok {
+		return fields.([]structField)
+	}
+	fields, _ := structFieldsCache.LoadOrStore(key, structFields(t, structTag))
+	return fields.([]structField)
+}
+
+// structFields lists the fields structToMap reads. When promoted fields share
+// a name, the shallowest one wins, then the only tagged one at that depth;
+// otherwise the name is ambiguous and, as in encoding/json, left out.
+func structFields(t reflect.Type, structTag string) []structField {
+	var fields []structField
+	byName := make(map[string]int)
+	ambiguous := make(map[string]bool)
+
+	type level struct {
+		t     reflect.Type
+		index []int
+	}
+	current := []level{{t: t}}
+	visited := map[reflect.Type]bool{}
+	for len(current) > 0 {
+		var next []level
+		found := make(map[string][]structField)
+		var order []string
+
+		for _, l := range current {
+			if visited[l.t] {
+				continue
+			}
+			visited[l.t] = true
+
+			for i := 0; i < l.t.NumField(); i++ {
+				f := l.t.Field(i)
+				name, tagged, skip := structFieldName(f, structTag)
+				if skip {
+					continue
+				}
+				index := append(slices.Clone(l.index), i)
+
+				if f.Anonymous && !tagged {
+					embedded := f.Type
+					if embedded.Kind() == reflect.Ptr {
+						embedded = embedded.Elem()
+					}
+					// Exported fields are promoted even from an unexported
+					// embedded struct, as in encoding/json.
+					if embedded.Kind() == reflect.Struct {
+						next = append(next, level{t: embedded, index: index})
+						continue
+					}
+				}
+				// An unexported field cannot be read with reflection, even
+				// when a tag names it.
+				if !f.IsExported() {
+					continue
+				}
+				if _, seen := found[name]; !seen {
+					order = append(order, name)
+				}
+				found[name] = append(found[name], structField{name: name, index: index, tagged: tagged})
 			}
 		}
 
-		result[fieldName] = fieldValue.Interface()
+		for _, name := range order {
+			if _, exists := byName[name]; exists || ambiguous[name] {
+				continue
+			}
+			candidates := found[name]
+			if len(candidates) > 1 {
+				var tagged []structField
+				for _, c := range candidates {
+					if c.tagged {
+						tagged = append(tagged, c)
+					}
+				}
+				if len(tagged) != 1 {
+					ambiguous[name] = true
+					continue
+				}
+				candidates = tagged
+			}
+			byName[name] = len(fields)
+			fields = append(fields, candidates[0])
+		}
+		current = next
 	}
+	return fields
+}
 
-	return result
-}
\ No newline at end of file
+// structFieldName names a struct field by structTag when set, then by its
+// json tag, then by its Go name. tagged reports whether a tag gave the name,
+// and skip whether the field is tagged "-". As in encoding/json, the name is
+// the part of the tag before the first comma, so "-," names a field "-" and
+// ",omitempty" keeps looking for a name.
+func structFieldName(field reflect.StructField, structTag string) (name string, tagged, skip bool) {
+	for _, key := range []string{structTag, "json"} {
+		if key == "" {
+			continue
+		}
+		tag := field.Tag.Get(key)
+		if tag == "-" {
+			return "", false, true
+		}
+		name, _, _ = strings.Cut(tag, ",")
+		if name != "" {
+			return name, true, false
+		}
+	}
+	return field.Name, false, false
+}
@@ -0,0 +1,179 @@
+package god
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+type ValidateOption func(*validationContext)
+
+type validationContext struct {
+	abortEarly bool
+	locale     string
+	buffers    *validationBuffers
+	clock      func() time.Time
+	coerce     bool
+	maxDepth   int
+	depth      int
+	structTag  string
+	maxBody    int64
+}
+
+// DefaultMaxDepth is how deeply nested objects and arrays may be before
+// validation stops with a too_deep error, unless WithMaxDepth changes it.
+const DefaultMaxDepth = 1000
+
+// newContext returns a context for a single validation. Contexts track the
+// current depth, so they are never shared between validations.
+func newContext() *validationContext {
+	return &validationContext{}
+}
+
+func WithAbortEarly() ValidateOption {
+	return func(ctx *validationContext) {
+		ctx.abortEarly = true
+	}
+}
+
+func WithLocale(locale string) ValidateOption {
+	return func(ctx *validationContext) {
+		ctx.locale = locale
+	}
+}
+
+// WithNow fixes the current time for a single validation, overriding the
+// clock set with SetClock.
+func WithNow(t time.Time) ValidateOption {
+	return func(ctx *validationContext) {
+		ctx.clock = func() time.Time { return t }
+	}
+}
+
+// WithCoerce coerces primitive inputs for every schema in the tree, as if
+// Coerce() had been called on each of them.
+func WithCoerce() ValidateOption {
+	return func(ctx *validationContext) {
+		ctx.coerce = true
+	}
+}
+
+// WithMaxDepth limits how deeply nested objects, records, maps, arrays and
+// tuples may be. Unions and other wrappers do not add a level.
+func WithMaxDepth(depth int) ValidateOption {
+	return func(ctx *validationContext) {
+		ctx.maxDepth = depth
+	}
+}
+
+// WithStructTag names struct fields by the given tag when validating structs,
+// falling back to the json tag and then the Go field name. The same struct
+// can then be validated with different field names for different purposes.
+func WithStructTag(tag string) ValidateOption {
+	return func(ctx *validationContext) {
+		ctx.structTag = tag
+	}
+}
+
+// WithMaxBodyBytes limits how many bytes BindJSON reads from a request body,
+// replacing DefaultMaxBodyBytes. Validations that read no body ignore it.
+func WithMaxBodyBytes(n int64) ValidateOption {
+	return func(ctx *validationContext) {
+		ctx.maxBody = n
+	}
+}
+
+// enter records one more level of nesting. Once the limit is reached it
+// returns a too_deep result instead, and the caller must not call leave.
+func (ctx *validationContext) enter() (ValidationResult, bool) {
+	limit := ctx.maxDepth
+	if limit <= 0 {
+		limit = DefaultMaxDepth
+	}
+	if ctx.depth >= limit {
+		return ValidationResult{
+			Valid: false,
+			Errors: []ValidationError{{
+				Message: fmt.Sprintf("input is nested more than %d levels deep", limit),
+				Code:    "too_deep",
+				Params:  map[string]interface{}{"maxDepth": limit},
+			}},
+		}, false
+	}
+	ctx.depth++
+	return ValidationResult{}, true
+}
+
+func (ctx *validationContext) leave() {
+	ctx.depth--
+}
+
+func (ctx *validationContext) now() time.Time {
+	if ctx.clock != nil {
+		return ctx.clock()
+	}
+	return now()
+}
+
+func Validate(schema Schema, value interface{}, opts ...ValidateOption) ValidationResult {
+	ctx := &validationContext{}
+	for _, opt := range opts {
+		opt(ctx)
+	}
+	return ctx.localize(validateChild(schema, value, ctx))
+}
+
+type ErrorFormatter func(err ValidationError, locale string) string
+
+// globalFormatter holds the formatter set with SetErrorFormatter, or nil for
+// DefaultErrorFormatter.
+var globalFormatter atomic.Pointer[ErrorFormatter]
+
+func DefaultErrorFormatter(err ValidationError, locale string) string {
+	return err.Message
+}
+
+// SetErrorFormatter sets the function that localizes messages when WithLocale
+// is used. Passing nil restores DefaultErrorFormatter. It is safe to call
+// while other goroutines validate.
+func SetErrorFormatter(formatter ErrorFormatter) {
+	if formatter == nil {
+		globalFormatter.Store(nil)
+		return
+	}
+	globalFormatter.Store(&formatter)
+}
+
+func (ctx *validationContext) localize(result ValidationResult) ValidationResult {
+	if ctx.locale == "" {
+		return result
+	}
+	errorFormatter := DefaultErrorFormatter
+	if formatter := globalFormatter.Load(); formatter != nil {
+		errorFormatter = *formatter
+	}
+	for i := range result.Errors {
+		result.Errors[i].Message = errorFormatter(result.Errors[i], ctx.locale)
+	}
+	for i := range result.Warnings {
+		result.Warnings[i].Message = errorFormatter(result.Warnings[i], ctx.locale)
+	}
+	return result
+}
+
+type contextSchema interface {
+	validate(value interface{}, ctx *validationContext) ValidationResult
+}
+
+func validateChild(schema Schema, value interface{}, ctx *validationContext) ValidationResult {
+	var result ValidationResult
+	if s, ok := schema.(contextSchema); ok {
+		result = s.validate(value, ctx)
+	} else {
+		result = schema.Validate(value)
+	}
+	if ctx.abortEarly && len(result.Errors) > 1 {
+		result.Errors = result.Errors[:1]
+	}
+	return result
+}
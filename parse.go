@@ -0,0 +1,71 @@
+package god
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+func ParseInto[T any](schema Schema, input interface{}) (T, error) {
+	var target T
+
+	result := schema.Validate(input)
+	if !result.Valid {
+		return target, result.Error()
+	}
+
+	if err := decodeInto(result.Value, &target); err != nil {
+		return target, err
+	}
+
+	return target, nil
+}
+
+type Result[T any] struct {
+	Valid  bool
+	Errors []ValidationError
+	Value  T
+}
+
+func (r Result[T]) Error() error {
+	return ValidationResult{Valid: r.Valid, Errors: r.Errors}.Error()
+}
+
+type TypedSchema[T any] struct {
+	schema Schema
+}
+
+func Typed[T any](schema Schema) *TypedSchema[T] {
+	return &TypedSchema[T]{schema: schema}
+}
+
+func (s *TypedSchema[T]) Schema() Schema {
+	return s.schema
+}
+
+func (s *TypedSchema[T]) Validate(value interface{}) Result[T] {
+	result := s.schema.Validate(value)
+	if !result.Valid {
+		return Result[T]{Valid: false, Errors: result.Errors}
+	}
+
+	var typed T
+	if err := decodeInto(result.Value, &typed); err != nil {
+		return Result[T]{
+			Valid:  false,
+			Errors: []ValidationError{{Message: err.Error(), Code: "invalid_type", Value: value}},
+		}
+	}
+
+	return Result[T]{Valid: true, Value: typed}
+}
+
+func decodeInto(value interface{}, target interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode validated value: %w", err)
+	}
+	if err := json.Unmarshal(data, target); err != nil {
+		return fmt.Errorf("failed to decode validated value: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package god
+
+// PreprocessSchema runs a function on the raw input before its inner schema
+// checks the type, for input the schema would otherwise reject, such as a
+// json.Number or a price with a currency symbol. Like Zod's z.preprocess.
+//
+// A nil input skips the function by default, so the inner schema's Optional,
+// Default and required checks apply as usual. IncludeNil passes nil to the
+// function too, so it can supply a value.
+type PreprocessSchema struct {
+	BaseSchema
+	schema     Schema
+	fn         func(interface{}) interface{}
+	includeNil bool
+}
+
+func Preprocess(fn func(interface{}) interface{}, schema Schema) *PreprocessSchema {
+	return &PreprocessSchema{
+		BaseSchema: BaseSchema{isRequired: true},
+		schema:     schema,
+		fn:         fn,
+	}
+}
+
+// IncludeNil runs the function on nil and missing input as well, before the
+// inner schema's nil handling.
+func (s *PreprocessSchema) IncludeNil() *PreprocessSchema {
+	s.includeNil = true
+	return s
+}
+
+func (s *PreprocessSchema) Optional() Schema {
+	s.schema = s.schema.Optional()
+	return s
+}
+
+func (s *PreprocessSchema) Required() Schema {
+	s.schema = s.schema.Required()
+	return s
+}
+
+func (s *PreprocessSchema) Default(value interface{}) Schema {
+	s.schema = s.schema.Default(value)
+	return s
+}
+
+func (s *PreprocessSchema) Validate(value interface{}) ValidationResult {
+	return s.validate(value, newContext())
+}
+
+func (s *PreprocessSchema) validate(value interface{}, ctx *validationContext) ValidationResult {
+	if s.includeNil || !isNil(value) {
+		value = s.fn(value)
+	}
+	return validateChild(s.schema, value, ctx)
+}
+
+func (s *StringSchema) Preprocess(fn func(interface{}) interface{}) *PreprocessSchema {
+	return Preprocess(fn, s)
+}
+
+func (s *NumberSchema) Preprocess(fn func(interface{}) interface{}) *PreprocessSchema {
+	return Preprocess(fn, s)
+}
+
+func (s *BigIntSchema) Preprocess(fn func(interface{}) interface{}) *PreprocessSchema {
+	return Preprocess(fn, s)
+}
+
+func (s *BooleanSchema) Preprocess(fn func(interface{}) interface{}) *PreprocessSchema {
+	return Preprocess(fn, s)
+}
+
+func (s *DateSchema) Preprocess(fn func(interface{}) interface{}) *PreprocessSchema {
+	return Preprocess(fn, s)
+}
+
+func (s *ObjectSchema) Preprocess(fn func(interface{}) interface{}) *PreprocessSchema {
+	return Preprocess(fn, s)
+}
+
+func (s *ArraySchema) Preprocess(fn func(interface{}) interface{}) *PreprocessSchema {
+	return Preprocess(fn, s)
+}
+
+func (s *TupleSchema) Preprocess(fn func(interface{}) interface{}) *PreprocessSchema {
+	return Preprocess(fn, s)
+}
+
+func (s *UnionSchema) Preprocess(fn func(interface{}) interface{}) *PreprocessSchema {
+	return Preprocess(fn, s)
+}
+
+func (s *DiscriminatedUnionSchema) Preprocess(fn func(interface{}) interface{}) *PreprocessSchema {
+	return Preprocess(fn, s)
+}
+
+func (s *LiteralSchema) Preprocess(fn func(interface{}) interface{}) *PreprocessSchema {
+	return Preprocess(fn, s)
+}
+
+func (s *EnumSchema) Preprocess(fn func(interface{}) interface{}) *PreprocessSchema {
+	return Preprocess(fn, s)
+}
+
+func (s *NullableSchema) Preprocess(fn func(interface{}) interface{}) *PreprocessSchema {
+	return Preprocess(fn, s)
+}
+
+func (s *AnySchema) Preprocess(fn func(interface{}) interface{}) *PreprocessSchema {
+	return Preprocess(fn, s)
+}
+
+func (s *UnknownSchema) Preprocess(fn func(interface{}) interface{}) *PreprocessSchema {
+	return Preprocess(fn, s)
+}
+
+func (s *OrderedSchema) Preprocess(fn func(interface{}) interface{}) *PreprocessSchema {
+	return Preprocess(fn, s)
+}
+
+func (s *TransformSchema) Preprocess(fn func(interface{}) interface{}) *PreprocessSchema {
+	return Preprocess(fn, s)
+}
+
+func (s *PipeSchema) Preprocess(fn func(interface{}) interface{}) *PreprocessSchema {
+	return Preprocess(fn, s)
+}
+
+func (s *RecordSchema) Preprocess(fn func(interface{}) interface{}) *PreprocessSchema {
+	return Preprocess(fn, s)
+}
+
+func (s *MapSchema) Preprocess(fn func(interface{}) interface{}) *PreprocessSchema {
+	return Preprocess(fn, s)
+}
+
+func (s *SetSchema) Preprocess(fn func(interface{}) interface{}) *PreprocessSchema {
+	return Preprocess(fn, s)
+}
+
+func (s *NativeEnumSchema[T]) Preprocess(fn func(interface{}) interface{}) *PreprocessSchema {
+	return Preprocess(fn, s)
+}
+
+func (s *CustomSchema) Preprocess(fn func(interface{}) interface{}) *PreprocessSchema {
+	return Preprocess(fn, s)
+}
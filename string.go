@@ -1,20 +1,126 @@
 package god
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"net/mail"
+	"net/url"
+	"reflect"
 	"regexp"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
 type StringSchema struct {
 	BaseSchema
-	minLength *int
-	maxLength *int
-	pattern   *regexp.Regexp
-	email     bool
-	url       bool
-	uuid      bool
-	transform func(string) string
+	minLength  *int
+	maxLength  *int
+	patterns   []*regexp.Regexp
+	groups     []regexGroups
+	email      bool
+	url        bool
+	datetime   *DatetimeOptions
+	startsWith *string
+	endsWith   *string
+	includes   *string
+	bytes      bool
+	coerce     bool
+	transforms []func(string) string
+	sanitizers []func(string) string
+	json       bool
+	jsonSchema Schema
+	base64     bool
+	base64URL  bool
+	decode64   bool
+	urlOptions urlOptions
+	emailMode  EmailMode
+	emailName  bool
+	formats    []*stringFormat
+	ascii      bool
+	emoji      bool
+}
+
+// stringFormat is a named pattern check. jsonFormat is the JSON Schema
+// "format" keyword for it, when one is standardized.
+type stringFormat struct {
+	name       string
+	pattern    *regexp.Regexp
+	jsonFormat string
+}
+
+// regexGroups validates the named capture groups of pattern against the
+// schema registered for each group name.
+type regexGroups struct {
+	pattern *regexp.Regexp
+	schemas map[string]Schema
+}
+
+var (
+	uuidFormat     = &stringFormat{name: "UUID", pattern: regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`), jsonFormat: "uuid"}
+	ulidFormat     = &stringFormat{name: "ULID", pattern: regexp.MustCompile(`^[0-7][0-9A-HJKMNP-TV-Za-hjkmnp-tv-z]{25}$`)}
+	cuidFormat     = &stringFormat{name: "CUID", pattern: regexp.MustCompile(`^c[a-z0-9]{24}$`)}
+	cuid2Format    = &stringFormat{name: "CUID2", pattern: regexp.MustCompile(`^[a-z][a-z0-9]{1,31}$`)}
+	nanoidFormat   = &stringFormat{name: "nanoid", pattern: regexp.MustCompile(`^[A-Za-z0-9_-]{21}$`)}
+	hexColorFormat = &stringFormat{name: "hex color", pattern: regexp.MustCompile(`^#(?:[0-9a-fA-F]{3}|[0-9a-fA-F]{6}|[0-9a-fA-F]{8})$`)}
+	slugFormat     = &stringFormat{name: "slug", pattern: regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)}
+)
+
+type EmailMode int
+
+const (
+	// EmailLenient checks addresses with a simple regular expression.
+	EmailLenient EmailMode = iota
+	// EmailStrict parses addresses with net/mail following RFC 5322, which
+	// accepts quoted local parts and internationalized domains.
+	EmailStrict
+)
+
+// RegexFlag is a set of matching options for RegexFlags, combined with |.
+type RegexFlag int
+
+const (
+	// RegexCaseInsensitive matches letters regardless of case.
+	RegexCaseInsensitive RegexFlag = 1 << iota
+	// RegexMultiline makes ^ and $ match at line boundaries.
+	RegexMultiline
+	// RegexDotAll lets . match newlines.
+	RegexDotAll
+)
+
+// prefix returns the inline flag group Go's regexp syntax uses for flags.
+func (f RegexFlag) prefix() string {
+	var flags string
+	if f&RegexCaseInsensitive != 0 {
+		flags += "i"
+	}
+	if f&RegexMultiline != 0 {
+		flags += "m"
+	}
+	if f&RegexDotAll != 0 {
+		flags += "s"
+	}
+	if flags == "" {
+		return ""
+	}
+	return "(?" + flags + ")"
+}
+
+type urlOptions struct {
+	schemes       []string
+	requireHost   bool
+	allowRelative bool
+}
+
+type DatetimeOptions struct {
+	Precision *int
+	Offset    bool
+	Local     bool
 }
 
 func String() *StringSchema {
@@ -23,163 +129,922 @@ func String() *StringSchema {
 	}
 }
 
+func CoerceString() *StringSchema {
+	return String().Coerce()
+}
+
+func (s *StringSchema) Coerce() *StringSchema {
+	s = s.derive()
+	s.coerce = true
+	return s
+}
+
 func (s *StringSchema) Min(length int) *StringSchema {
+	s = s.derive()
 	s.minLength = &length
 	return s
 }
 
 func (s *StringSchema) Max(length int) *StringSchema {
+	s = s.derive()
 	s.maxLength = &length
 	return s
 }
 
 func (s *StringSchema) Length(length int) *StringSchema {
+	s = s.derive()
 	s.minLength = &length
 	s.maxLength = &length
 	return s
 }
 
+func (s *StringSchema) Bytes() *StringSchema {
+	s = s.derive()
+	s.bytes = true
+	return s
+}
+
+// Regex panics if pattern does not compile. Use RegexErr for patterns that
+// are not literals, such as ones loaded from configuration. Repeated calls
+// accumulate; the string must match every pattern.
 func (s *StringSchema) Regex(pattern string) *StringSchema {
-	s.pattern = regexp.MustCompile(pattern)
+	s = s.derive()
+	re, err := compileRegex(pattern)
+	if err != nil {
+		panic(err)
+	}
+	s.patterns = append(s.patterns, re)
+	return s
+}
+
+// RegexErr is Regex returning the compile error instead of panicking. Its
+// patterns are not cached, since they often come from outside the program.
+func (s *StringSchema) RegexErr(pattern string) (*StringSchema, error) {
+	s = s.derive()
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return s, err
+	}
+	s.patterns = append(s.patterns, re)
+	return s, nil
+}
+
+// RegexFlags is Regex with matching options, so callers need not write
+// Go's inline flag syntax themselves.
+func (s *StringSchema) RegexFlags(pattern string, flags RegexFlag) *StringSchema {
+	return s.Regex(flags.prefix() + pattern)
+}
+
+// RegexGroups requires the string to match pattern and validates each named
+// capture group against its schema. A group that did not participate in the
+// match is validated as nil. It panics if pattern does not compile or a key
+// of groupSchemas is not a group name in pattern.
+func (s *StringSchema) RegexGroups(pattern string, groupSchemas map[string]Schema) *StringSchema {
+	s = s.derive()
+	re, err := compileRegex(pattern)
+	if err != nil {
+		panic(err)
+	}
+	for name := range groupSchemas {
+		if re.SubexpIndex(name) < 0 {
+			panic(fmt.Sprintf("god: pattern %q has no capture group named %q", pattern, name))
+		}
+	}
+	s.groups = append(s.groups, regexGroups{pattern: re, schemas: groupSchemas})
+	return s
+}
+
+func (g regexGroups) validate(str string, ctx *validationContext) []ValidationError {
+	match := g.pattern.FindStringSubmatchIndex(str)
+	if match == nil {
+		return []ValidationError{{
+			Message: fmt.Sprintf("string does not match required pattern %q", g.pattern.String()),
+			Code:    "invalid_string",
+			Value:   str,
+			Params:  map[string]interface{}{"pattern": g.pattern.String()},
+		}}
+	}
+
+	var errors []ValidationError
+	for _, name := range sortedKeys(g.schemas) {
+		index := g.pattern.SubexpIndex(name)
+		var value interface{}
+		if start := match[2*index]; start >= 0 {
+			value = str[start:match[2*index+1]]
+		}
+		result := validateChild(g.schemas[name], value, ctx)
+		for _, err := range result.Errors {
+			err = err.withPrefix(keySegment(name))
+			if err.Params == nil {
+				err.Params = map[string]interface{}{}
+			}
+			err.Params["pattern"] = g.pattern.String()
+			err.Params["group"] = name
+			errors = append(errors, err)
+		}
+	}
+	return errors
+}
+
+// regexCache holds compiled patterns so rebuilding a schema with the same
+// pattern does not recompile it. A *regexp.Regexp is safe for concurrent use.
+// Only Regex and RegexGroups use it, and it stops growing at maxCachedRegexes
+// patterns so programs that build patterns at runtime do not leak memory.
+var (
+	regexCache     sync.Map
+	regexCacheSize atomic.Int64
+)
+
+const maxCachedRegexes = 1024
+
+func compileRegex(pattern string) (*regexp.Regexp, error) {
+	if cached, ok := regexCache.Load(pattern); ok {
+		return cached.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	if regexCacheSize.Load() < maxCachedRegexes {
+		if _, loaded := regexCache.LoadOrStore(pattern, re); !loaded {
+			regexCacheSize.Add(1)
+		}
+	}
+	return re, nil
+}
+
+func (s *StringSchema) Email(mode ...EmailMode) *StringSchema {
+	s = s.derive()
+	s.email = true
+	if len(mode) > 0 {
+		s.emailMode = mode[0]
+	}
 	return s
 }
 
-func (s *StringSchema) Email() *StringSchema {
+// AllowDisplayName accepts "John <john@example.com>" and returns the bare
+// address. Addresses are then parsed with net/mail as in EmailStrict.
+func (s *StringSchema) AllowDisplayName() *StringSchema {
+	s = s.derive()
 	s.email = true
+	s.emailName = true
 	return s
 }
 
 func (s *StringSchema) URL() *StringSchema {
+	s = s.derive()
 	s.url = true
 	return s
 }
 
+// Schemes restricts URL to the given schemes, compared case-insensitively.
+func (s *StringSchema) Schemes(schemes ...string) *StringSchema {
+	s = s.derive()
+	s.url = true
+	s.urlOptions.schemes = append(s.urlOptions.schemes, schemes...)
+	return s
+}
+
+// RequireHost rejects URLs without a host, such as mailto:a@b.co.
+func (s *StringSchema) RequireHost() *StringSchema {
+	s = s.derive()
+	s.url = true
+	s.urlOptions.requireHost = true
+	return s
+}
+
+// AllowRelative accepts relative references such as /avatar.jpg.
+func (s *StringSchema) AllowRelative() *StringSchema {
+	s = s.derive()
+	s.url = true
+	s.urlOptions.allowRelative = true
+	return s
+}
+
 func (s *StringSchema) UUID() *StringSchema {
-	s.uuid = true
+	s = s.derive()
+	s.formats = append(s.formats, uuidFormat)
+	return s
+}
+
+func (s *StringSchema) ULID() *StringSchema {
+	s = s.derive()
+	s.formats = append(s.formats, ulidFormat)
+	return s
+}
+
+func (s *StringSchema) CUID() *StringSchema {
+	s = s.derive()
+	s.formats = append(s.formats, cuidFormat)
+	return s
+}
+
+func (s *StringSchema) CUID2() *StringSchema {
+	s = s.derive()
+	s.formats = append(s.formats, cuid2Format)
+	return s
+}
+
+func (s *StringSchema) Nanoid() *StringSchema {
+	s = s.derive()
+	s.formats = append(s.formats, nanoidFormat)
 	return s
 }
 
+func (s *StringSchema) HexColor() *StringSchema {
+	s = s.derive()
+	s.formats = append(s.formats, hexColorFormat)
+	return s
+}
+
+func (s *StringSchema) Slug() *StringSchema {
+	s = s.derive()
+	s.formats = append(s.formats, slugFormat)
+	return s
+}
+
+func (s *StringSchema) Ascii() *StringSchema {
+	s = s.derive()
+	s.ascii = true
+	return s
+}
+
+// Emoji requires the string to consist only of emoji, including sequences
+// joined with ZWJ, skin tone modifiers, flags and keycaps.
+func (s *StringSchema) Emoji() *StringSchema {
+	s = s.derive()
+	s.emoji = true
+	return s
+}
+
+func (s *StringSchema) Datetime(opts ...DatetimeOptions) *StringSchema {
+	s = s.derive()
+	s.datetime = &DatetimeOptions{}
+	if len(opts) > 0 {
+		// Copy the options so later changes to the caller's slice or
+		// precision do not reach the schema.
+		o := opts[0]
+		if o.Precision != nil {
+			precision := *o.Precision
+			o.Precision = &precision
+		}
+		s.datetime = &o
+	}
+	return s
+}
+
+func (s *StringSchema) Base64() *StringSchema {
+	s = s.derive()
+	s.base64 = true
+	return s
+}
+
+// Base64URL accepts the URL-safe alphabet, with or without padding.
+func (s *StringSchema) Base64URL() *StringSchema {
+	s = s.derive()
+	s.base64URL = true
+	return s
+}
+
+// DecodeBase64 returns the decoded []byte as the validated value. It implies
+// Base64 unless Base64URL was chosen.
+func (s *StringSchema) DecodeBase64() *StringSchema {
+	s = s.derive()
+	s.decode64 = true
+	if !s.base64URL {
+		s.base64 = true
+	}
+	return s
+}
+
+func (s *StringSchema) JSON() *StringSchema {
+	s = s.derive()
+	s.json = true
+	return s
+}
+
+// JSONSchema parses the string as JSON and validates the result with inner.
+// The parsed value is returned, and inner errors are reported under "json".
+func (s *StringSchema) JSONSchema(inner Schema) *StringSchema {
+	s = s.derive()
+	s.json = true
+	s.jsonSchema = inner
+	return s
+}
+
+func (s *StringSchema) StartsWith(prefix string) *StringSchema {
+	s = s.derive()
+	s.startsWith = &prefix
+	return s
+}
+
+func (s *StringSchema) EndsWith(suffix string) *StringSchema {
+	s = s.derive()
+	s.endsWith = &suffix
+	return s
+}
+
+func (s *StringSchema) Includes(substr string) *StringSchema {
+	s = s.derive()
+	s.includes = &substr
+	return s
+}
+
+// Transform adds fn to the transforms that rewrite the string before it is
+// checked. Transforms compose: each runs on the result of the one before, in
+// the order they were added, so Trim().ToLower() trims and then lowercases.
 func (s *StringSchema) Transform(fn func(string) string) *StringSchema {
-	s.transform = fn
+	s = s.derive()
+	s.transforms = append(s.transforms, fn)
 	return s
 }
 
 func (s *StringSchema) Trim() *StringSchema {
-	s.transform = strings.TrimSpace
-	return s
+	return s.Transform(strings.TrimSpace)
+}
+
+// TrimLeft removes leading whitespace.
+func (s *StringSchema) TrimLeft() *StringSchema {
+	return s.Transform(func(str string) string {
+		return strings.TrimLeftFunc(str, unicode.IsSpace)
+	})
+}
+
+// TrimRight removes trailing whitespace.
+func (s *StringSchema) TrimRight() *StringSchema {
+	return s.Transform(func(str string) string {
+		return strings.TrimRightFunc(str, unicode.IsSpace)
+	})
+}
+
+func (s *StringSchema) TrimPrefix(prefix string) *StringSchema {
+	return s.Transform(func(str string) string {
+		return strings.TrimPrefix(str, prefix)
+	})
+}
+
+func (s *StringSchema) TrimSuffix(suffix string) *StringSchema {
+	return s.Transform(func(str string) string {
+		return strings.TrimSuffix(str, suffix)
+	})
+}
+
+// CollapseWhitespace replaces each run of whitespace with a single space.
+// Leading and trailing runs become a single space too; add Trim to remove
+// them.
+func (s *StringSchema) CollapseWhitespace() *StringSchema {
+	return s.Transform(collapseWhitespace)
 }
 
 func (s *StringSchema) ToLower() *StringSchema {
-	s.transform = strings.ToLower
-	return s
+	return s.Transform(strings.ToLower)
 }
 
 func (s *StringSchema) ToUpper() *StringSchema {
-	s.transform = strings.ToUpper
+	return s.Transform(strings.ToUpper)
+}
+
+func (s *StringSchema) NormalizeEmail() *StringSchema {
+	s = s.Trim().ToLower()
+	s.email = true
 	return s
 }
 
+func (s *StringSchema) Sanitize(fn func(string) string) *StringSchema {
+	s = s.derive()
+	s.sanitizers = append(s.sanitizers, fn)
+	return s
+}
+
+func (s *StringSchema) StripHTML() *StringSchema {
+	return s.Sanitize(stripHTML)
+}
+
+func (s *StringSchema) StripControl() *StringSchema {
+	return s.Sanitize(stripControl)
+}
+
 func (s *StringSchema) Optional() Schema {
+	s = s.derive()
 	s.BaseSchema.setOptional()
 	return s
 }
 
 func (s *StringSchema) Required() Schema {
+	s = s.derive()
 	s.BaseSchema.setRequired()
 	return s
 }
 
 func (s *StringSchema) Default(value interface{}) Schema {
+	s = s.derive()
 	s.BaseSchema.setDefault(value)
 	return s
 }
 
+func (s *StringSchema) DefaultFunc(fn func() interface{}) Schema {
+	s = s.derive()
+	s.BaseSchema.setDefaultFunc(fn)
+	return s
+}
+
+func (s *StringSchema) Readonly() *StringSchema {
+	s = s.derive()
+	s.BaseSchema.setReadonly()
+	return s
+}
+
+func (s *StringSchema) WithMessage(code, message string) *StringSchema {
+	s = s.derive()
+	s.BaseSchema.setMessage(code, message)
+	return s
+}
+
 func (s *StringSchema) Validate(value interface{}) ValidationResult {
+	return s.validate(value, newContext())
+}
+
+func (s *StringSchema) validate(value interface{}, ctx *validationContext) ValidationResult {
 	processedValue, shouldReturn, result := s.handleNil(value)
 	if shouldReturn {
 		return result
 	}
 
 	str, ok := processedValue.(string)
+	if !ok && (s.coerce || ctx.coerce) {
+		str, ok = coerceToString(processedValue)
+	}
 	if !ok {
 		return ValidationResult{
 			Valid:  false,
-			Errors: []ValidationError{{Message: "expected string", Code: "invalid_type", Value: value}},
+			Errors: s.applyMessages([]ValidationError{{Message: "expected string", Code: "invalid_type", Value: value, Params: map[string]interface{}{"expected": "string"}}}),
 		}
 	}
 
-	if s.transform != nil {
-		str = s.transform(str)
+	// Transforms and sanitizers run first, and every check below sees their
+	// result, in a fixed order: length, then patterns, then formats.
+	for _, transform := range s.transforms {
+		str = transform(str)
+	}
+
+	var warnings []ValidationError
+	if len(s.sanitizers) > 0 {
+		original := str
+		for _, sanitize := range s.sanitizers {
+			str = sanitize(str)
+		}
+		if str != original {
+			warnings = append(warnings, ValidationError{
+				Message: "string was sanitized",
+				Code:    "sanitized",
+				Value:   original,
+			})
+		}
 	}
 
 	var errors []ValidationError
 
-	if s.minLength != nil && len(str) < *s.minLength {
+	length, unit := utf8.RuneCountInString(str), "characters"
+	if s.bytes {
+		length, unit = len(str), "bytes"
+	}
+
+	if s.minLength != nil && length < *s.minLength {
 		errors = append(errors, ValidationError{
-			Message: fmt.Sprintf("string must be at least %d characters", *s.minLength),
+			Message: fmt.Sprintf("string must be at least %d %s", *s.minLength, unit),
 			Code:    "too_small",
 			Value:   str,
+			Params:  map[string]interface{}{"min": *s.minLength},
 		})
 	}
 
-	if s.maxLength != nil && len(str) > *s.maxLength {
+	if s.maxLength != nil && length > *s.maxLength {
 		errors = append(errors, ValidationError{
-			Message: fmt.Sprintf("string must be at most %d characters", *s.maxLength),
+			Message: fmt.Sprintf("string must be at most %d %s", *s.maxLength, unit),
 			Code:    "too_big",
 			Value:   str,
+			Params:  map[string]interface{}{"max": *s.maxLength},
+		})
+	}
+
+	for _, pattern := range s.patterns {
+		if !pattern.MatchString(str) {
+			errors = append(errors, ValidationError{
+				Message: fmt.Sprintf("string does not match required pattern %q", pattern.String()),
+				Code:    "invalid_string",
+				Value:   str,
+				Params:  map[string]interface{}{"pattern": pattern.String()},
+			})
+		}
+	}
+
+	for _, group := range s.groups {
+		errors = append(errors, group.validate(str, ctx)...)
+	}
+
+	if s.startsWith != nil && !strings.HasPrefix(str, *s.startsWith) {
+		errors = append(errors, ValidationError{
+			Message: fmt.Sprintf("string must start with %q", *s.startsWith),
+			Code:    "invalid_string",
+			Value:   str,
+			Params:  map[string]interface{}{"startsWith": *s.startsWith},
+		})
+	}
+
+	if s.endsWith != nil && !strings.HasSuffix(str, *s.endsWith) {
+		errors = append(errors, ValidationError{
+			Message: fmt.Sprintf("string must end with %q", *s.endsWith),
+			Code:    "invalid_string",
+			Value:   str,
+			Params:  map[string]interface{}{"endsWith": *s.endsWith},
 		})
 	}
 
-	if s.pattern != nil && !s.pattern.MatchString(str) {
+	if s.includes != nil && !strings.Contains(str, *s.includes) {
 		errors = append(errors, ValidationError{
-			Message: "string does not match required pattern",
+			Message: fmt.Sprintf("string must include %q", *s.includes),
 			Code:    "invalid_string",
 			Value:   str,
+			Params:  map[string]interface{}{"includes": *s.includes},
 		})
 	}
 
-	if s.email && !isValidEmail(str) {
+	if s.email {
+		if address, ok := s.parseEmail(str); ok {
+			str = address
+		} else {
+			errors = append(errors, ValidationError{
+				Message: "invalid email format",
+				Code:    "invalid_string",
+				Value:   str,
+				Params:  map[string]interface{}{"format": "email"},
+			})
+		}
+	}
+
+	if s.url {
+		if message := validateURL(str, s.urlOptions); message != "" {
+			errors = append(errors, ValidationError{
+				Message: message,
+				Code:    "invalid_string",
+				Value:   str,
+				Params:  map[string]interface{}{"format": "url"},
+			})
+		}
+	}
+
+	for _, format := range s.formats {
+		if !format.pattern.MatchString(str) {
+			errors = append(errors, ValidationError{
+				Message: fmt.Sprintf("invalid %s format", format.name),
+				Code:    "invalid_string",
+				Value:   str,
+				Params:  map[string]interface{}{"format": format.name},
+			})
+		}
+	}
+
+	if s.ascii && !isASCII(str) {
 		errors = append(errors, ValidationError{
-			Message: "invalid email format",
+			Message: "string must contain only ASCII characters",
 			Code:    "invalid_string",
 			Value:   str,
+			Params:  map[string]interface{}{"format": "ascii"},
 		})
 	}
 
-	if s.url && !isValidURL(str) {
+	if s.emoji && !isEmojiOnly(str) {
 		errors = append(errors, ValidationError{
-			Message: "invalid URL format",
+			Message: "string must contain only emoji",
 			Code:    "invalid_string",
 			Value:   str,
+			Params:  map[string]interface{}{"format": "emoji"},
 		})
 	}
 
-	if s.uuid && !isValidUUID(str) {
+	if s.datetime != nil {
+		if message := validateDatetime(str, *s.datetime); message != "" {
+			errors = append(errors, ValidationError{
+				Message: message,
+				Code:    "invalid_string",
+				Value:   str,
+				Params:  map[string]interface{}{"format": "datetime"},
+			})
+		}
+	}
+
+	var decoded []byte
+	if s.base64 || s.base64URL {
+		var message string
+		decoded, message = decodeBase64(str, s.base64URL)
+		if message != "" {
+			base64Name := "base64"
+			if s.base64URL {
+				base64Name = "base64url"
+			}
+			errors = append(errors, ValidationError{
+				Message: message,
+				Code:    "invalid_string",
+				Value:   str,
+				Params:  map[string]interface{}{"format": base64Name},
+			})
+		}
+	}
+
+	if s.json && !json.Valid([]byte(str)) {
 		errors = append(errors, ValidationError{
-			Message: "invalid UUID format",
+			Message: "invalid JSON",
 			Code:    "invalid_string",
 			Value:   str,
+			Params:  map[string]interface{}{"format": "json"},
 		})
 	}
 
 	if len(errors) > 0 {
-		return ValidationResult{Valid: false, Errors: errors}
+		return ValidationResult{Valid: false, Errors: s.applyMessages(errors), Warnings: warnings}
+	}
+
+	if s.jsonSchema != nil {
+		var parsed interface{}
+		_ = json.Unmarshal([]byte(str), &parsed)
+
+		result := validateChild(s.jsonSchema, parsed, ctx)
+		for _, warning := range result.Warnings {
+			warnings = append(warnings, warning.withPrefix(keySegment("json")))
+		}
+		if !result.Valid {
+			for _, err := range result.Errors {
+				errors = append(errors, err.withPrefix(keySegment("json")))
+			}
+			return ValidationResult{Valid: false, Errors: errors, Warnings: warnings}
+		}
+		return ValidationResult{Valid: true, Value: result.Value, Warnings: warnings}
+	}
+
+	if s.decode64 {
+		return ValidationResult{Valid: true, Value: decoded, Warnings: warnings}
 	}
 
-	return ValidationResult{Valid: true, Value: str}
+	// Returning the input interface when the string is unchanged avoids
+	// boxing str into a new one.
+	if original, ok := processedValue.(string); ok && original == str {
+		return ValidationResult{Valid: true, Value: processedValue, Warnings: warnings}
+	}
+	return ValidationResult{Valid: true, Value: str, Warnings: warnings}
 }
 
+// decodeBase64 decodes str and, on failure, explains whether an illegal
+// character or bad padding was the cause.
+func decodeBase64(str string, urlSafe bool) ([]byte, string) {
+	alphabet := "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/"
+	encoding := base64.StdEncoding
+	if urlSafe {
+		alphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_"
+		encoding = base64.URLEncoding
+		if !strings.Contains(str, "=") {
+			encoding = base64.RawURLEncoding
+		}
+	}
+
+	data := strings.TrimRight(str, "=")
+	for i, r := range data {
+		if !strings.ContainsRune(alphabet, r) {
+			return nil, fmt.Sprintf("invalid base64: illegal character %q at position %d", r, i)
+		}
+	}
+
+	decoded, err := encoding.DecodeString(str)
+	if err != nil {
+		return nil, "invalid base64: incorrect padding"
+	}
+	return decoded, ""
+}
+
+func coerceToString(value interface{}) (string, bool) {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), true
+	case reflect.Float32:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 32), true
+	case reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64), true
+	case reflect.String:
+		return v.String(), true
+	}
+	return "", false
+}
+
+var htmlTagRegex = regexp.MustCompile(`<[^>]*>`)
+
+func stripHTML(str string) string {
+	return htmlTagRegex.ReplaceAllString(str, "")
+}
+
+func stripControl(str string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) && r != '\n' && r != '\r' && r != '\t' {
+			return -1
+		}
+		return r
+	}, str)
+}
+
+func collapseWhitespace(str string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range str {
+		if unicode.IsSpace(r) {
+			space = true
+			continue
+		}
+		if space {
+			b.WriteByte(' ')
+			space = false
+		}
+		b.WriteRune(r)
+	}
+	if space {
+		b.WriteByte(' ')
+	}
+	return b.String()
+}
+
+func (s *StringSchema) parseEmail(email string) (string, bool) {
+	if s.emailMode == EmailLenient && !s.emailName {
+		return email, isValidEmail(email)
+	}
+
+	address, err := mail.ParseAddress(email)
+	if err != nil {
+		return "", false
+	}
+	if address.Name == "" && !strings.HasSuffix(email, ">") {
+		return email, true
+	}
+	if !s.emailName {
+		return "", false
+	}
+	return address.Address, true
+}
+
+func isASCII(str string) bool {
+	for _, r := range str {
+		if r > unicode.MaxASCII {
+			return false
+		}
+	}
+	return true
+}
+
+const (
+	zeroWidthJoiner    = '\u200D'
+	variationEmoji     = '\uFE0F'
+	combiningKeycap    = '\u20E3'
+	regionalIndicatorA = '\U0001F1E6'
+	regionalIndicatorZ = '\U0001F1FF'
+)
+
+var emojiRanges = [][2]rune{
+	{0x00A9, 0x00A9}, {0x00AE, 0x00AE}, {0x203C, 0x203C}, {0x2049, 0x2049},
+	{0x2122, 0x2122}, {0x2139, 0x2139}, {0x2194, 0x21AA}, {0x231A, 0x23FF},
+	{0x24C2, 0x24C2}, {0x25AA, 0x25FE}, {0x2600, 0x27BF}, {0x2934, 0x2935},
+	{0x2B05, 0x2B55}, {0x3030, 0x3030}, {0x303D, 0x303D}, {0x3297, 0x3299},
+	{0x1F000, 0x1F2FF}, {0x1F300, 0x1FAFF},
+}
+
+func isEmojiRune(r rune) bool {
+	for _, bounds := range emojiRanges {
+		if r >= bounds[0] && r <= bounds[1] {
+			return true
+		}
+	}
+	return false
+}
+
+func isEmojiModifier(r rune) bool {
+	return r == variationEmoji ||
+		(r >= 0x1F3FB && r <= 0x1F3FF) || // skin tones
+		(r >= 0xE0020 && r <= 0xE007F) // tag sequences used by subdivision flags
+}
+
+// isEmojiOnly walks the string one emoji cluster at a time. A cluster is a
+// keycap sequence, a regional indicator pair, or an emoji followed by
+// modifiers and further ZWJ-joined emoji. The empty string has no emoji, so
+// it does not count.
+func isEmojiOnly(str string) bool {
+	if str == "" {
+		return false
+	}
+	runes := []rune(str)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case strings.ContainsRune("0123456789#*", r):
+			i++
+			if i < len(runes) && runes[i] == variationEmoji {
+				i++
+			}
+			if i >= len(runes) || runes[i] != combiningKeycap {
+				return false
+			}
+			i++
+		case r >= regionalIndicatorA && r <= regionalIndicatorZ:
+			if i+1 >= len(runes) || runes[i+1] < regionalIndicatorA || runes[i+1] > regionalIndicatorZ {
+				return false
+			}
+			i += 2
+		case isEmojiRune(r):
+			i++
+			for i < len(runes) {
+				if isEmojiModifier(runes[i]) {
+					i++
+				} else if runes[i] == zeroWidthJoiner && i+1 < len(runes) && isEmojiRune(runes[i+1]) {
+					i += 2
+				} else {
+					break
+				}
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+var emailRegex = regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
+
 func isValidEmail(email string) bool {
-	emailRegex := regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
 	return emailRegex.MatchString(email)
 }
 
-func isValidURL(url string) bool {
-	urlRegex := regexp.MustCompile(`^https?://[^\s/$.?#].[^\s]*$`)
-	return urlRegex.MatchString(url)
+func validateURL(str string, opts urlOptions) string {
+	if str == "" || strings.ContainsFunc(str, unicode.IsSpace) {
+		return "invalid URL format"
+	}
+	u, err := url.Parse(str)
+	if err != nil {
+		return "invalid URL format"
+	}
+
+	if u.Scheme == "" {
+		if !opts.allowRelative {
+			return "URL must be absolute"
+		}
+	} else if u.Hostname() == "" && u.Opaque == "" {
+		return "invalid URL format"
+	}
+
+	if len(opts.schemes) > 0 && u.Scheme != "" {
+		allowed := false
+		for _, scheme := range opts.schemes {
+			if strings.EqualFold(scheme, u.Scheme) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return fmt.Sprintf("URL scheme must be one of %s", strings.Join(opts.schemes, ", "))
+		}
+	}
+
+	if opts.requireHost && u.Hostname() == "" {
+		return "URL must include a host"
+	}
+
+	return ""
 }
 
-func isValidUUID(uuid string) bool {
-	uuidRegex := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
-	return uuidRegex.MatchString(strings.ToLower(uuid))
-}
\ No newline at end of file
+var datetimeRegex = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2})(?:\.(\d+))?(Z|[+-]\d{2}:\d{2})?$`)
+
+func validateDatetime(str string, opts DatetimeOptions) string {
+	match := datetimeRegex.FindStringSubmatch(str)
+	if match == nil {
+		return "invalid datetime format"
+	}
+	if _, err := time.Parse("2006-01-02T15:04:05", match[1]); err != nil {
+		return "invalid datetime format"
+	}
+
+	fraction, zone := match[2], match[3]
+
+	if opts.Precision != nil && len(fraction) != *opts.Precision {
+		if *opts.Precision == 0 {
+			return "datetime must not have fractional seconds"
+		}
+		return fmt.Sprintf("datetime must have exactly %d fractional second digits", *opts.Precision)
+	}
+
+	switch {
+	case zone == "" && !opts.Local:
+		return "datetime must include a timezone"
+	case zone != "" && zone != "Z" && !opts.Offset:
+		return "datetime must use Z instead of a timezone offset"
+	}
+
+	return ""
+}
@@ -0,0 +1,293 @@
+package god
+
+import "slices"
+
+type TransformSchema struct {
+	BaseSchema
+	schema     Schema
+	transforms []func(interface{}) (interface{}, error)
+}
+
+func Transform(schema Schema, fn func(interface{}) (interface{}, error)) *TransformSchema {
+	return &TransformSchema{
+		BaseSchema: BaseSchema{isRequired: true},
+		schema:     schema,
+		transforms: []func(interface{}) (interface{}, error){fn},
+	}
+}
+
+// Transform returns a new schema that runs fn after s's transforms, so
+// several chains can be derived from one shared transform.
+func (s *TransformSchema) Transform(fn func(interface{}) (interface{}, error)) *TransformSchema {
+	clone := *s
+	clone.transforms = append(slices.Clip(s.transforms), fn)
+	return &clone
+}
+
+func (s *TransformSchema) Optional() Schema {
+	s.schema = s.schema.Optional()
+	return s
+}
+
+func (s *TransformSchema) Required() Schema {
+	s.schema = s.schema.Required()
+	return s
+}
+
+func (s *TransformSchema) Default(value interface{}) Schema {
+	s.schema = s.schema.Default(value)
+	return s
+}
+
+func (s *TransformSchema) WithMessage(code, message string) *TransformSchema {
+	s.BaseSchema.setMessage(code, message)
+	return s
+}
+
+func (s *TransformSchema) Validate(value interface{}) ValidationResult {
+	return s.validate(value, newContext())
+}
+
+func (s *TransformSchema) validate(value interface{}, ctx *validationContext) ValidationResult {
+	result := validateChild(s.schema, value, ctx)
+	if !result.Valid || result.Value == nil {
+		return result
+	}
+
+	transformed := result.Value
+	for _, fn := range s.transforms {
+		next, err := fn(transformed)
+		if err != nil {
+			return ValidationResult{
+				Valid:    false,
+				Errors:   s.applyMessages([]ValidationError{{Message: err.Error(), Code: "transform", Value: transformed}}),
+				Warnings: result.Warnings,
+			}
+		}
+		transformed = next
+	}
+
+	return ValidationResult{Valid: true, Value: transformed, Warnings: result.Warnings}
+}
+
+func (s *NumberSchema) Transform(fn func(interface{}) (interface{}, error)) *TransformSchema {
+	return Transform(s, fn)
+}
+
+func (s *BigIntSchema) Transform(fn func(interface{}) (interface{}, error)) *TransformSchema {
+	return Transform(s, fn)
+}
+
+func (s *RecordSchema) Transform(fn func(interface{}) (interface{}, error)) *TransformSchema {
+	return Transform(s, fn)
+}
+
+func (s *MapSchema) Transform(fn func(interface{}) (interface{}, error)) *TransformSchema {
+	return Transform(s, fn)
+}
+
+func (s *BooleanSchema) Transform(fn func(interface{}) (interface{}, error)) *TransformSchema {
+	return Transform(s, fn)
+}
+
+func (s *DateSchema) Transform(fn func(interface{}) (interface{}, error)) *TransformSchema {
+	return Transform(s, fn)
+}
+
+func (s *ObjectSchema) Transform(fn func(interface{}) (interface{}, error)) *TransformSchema {
+	return Transform(s, fn)
+}
+
+func (s *ArraySchema) Transform(fn func(interface{}) (interface{}, error)) *TransformSchema {
+	return Transform(s, fn)
+}
+
+func (s *SetSchema) Transform(fn func(interface{}) (interface{}, error)) *TransformSchema {
+	return Transform(s, fn)
+}
+
+func (s *TupleSchema) Transform(fn func(interface{}) (interface{}, error)) *TransformSchema {
+	return Transform(s, fn)
+}
+
+func (s *UnionSchema) Transform(fn func(interface{}) (interface{}, error)) *TransformSchema {
+	return Transform(s, fn)
+}
+
+func (s *DiscriminatedUnionSchema) Transform(fn func(interface{}) (interface{}, error)) *TransformSchema {
+	return Transform(s, fn)
+}
+
+func (s *LiteralSchema) Transform(fn func(interface{}) (interface{}, error)) *TransformSchema {
+	return Transform(s, fn)
+}
+
+func (s *EnumSchema) Transform(fn func(interface{}) (interface{}, error)) *TransformSchema {
+	return Transform(s, fn)
+}
+
+func (s *NativeEnumSchema[T]) Transform(fn func(interface{}) (interface{}, error)) *TransformSchema {
+	return Transform(s, fn)
+}
+
+func (s *NullableSchema) Transform(fn func(interface{}) (interface{}, error)) *TransformSchema {
+	return Transform(s, fn)
+}
+
+func (s *AnySchema) Transform(fn func(interface{}) (interface{}, error)) *TransformSchema {
+	return Transform(s, fn)
+}
+
+func (s *UnknownSchema) Transform(fn func(interface{}) (interface{}, error)) *TransformSchema {
+	return Transform(s, fn)
+}
+
+func (s *OrderedSchema) Transform(fn func(interface{}) (interface{}, error)) *TransformSchema {
+	return Transform(s, fn)
+}
+
+func (s *CustomSchema) Transform(fn func(interface{}) (interface{}, error)) *TransformSchema {
+	return Transform(s, fn)
+}
+
+// PipeSchema validates a value with one schema and passes the validated value
+// on to a second. Warnings from both stages are kept.
+type PipeSchema struct {
+	BaseSchema
+	from Schema
+	to   Schema
+}
+
+// Pipe validates with from and then validates from's output with to. When from
+// fails, its errors are returned and to never runs; otherwise the result is
+// to's, so only one stage's errors are ever reported. A nil value from from is
+// returned as is, without running to.
+func Pipe(from Schema, to Schema) *PipeSchema {
+	return &PipeSchema{
+		BaseSchema: BaseSchema{isRequired: true},
+		from:       from,
+		to:         to,
+	}
+}
+
+func (s *PipeSchema) Pipe(next Schema) *PipeSchema {
+	return Pipe(s, next)
+}
+
+func (s *PipeSchema) Optional() Schema {
+	s.from = s.from.Optional()
+	return s
+}
+
+func (s *PipeSchema) Required() Schema {
+	s.from = s.from.Required()
+	return s
+}
+
+func (s *PipeSchema) Default(value interface{}) Schema {
+	s.from = s.from.Default(value)
+	return s
+}
+
+func (s *PipeSchema) Validate(value interface{}) ValidationResult {
+	return s.validate(value, newContext())
+}
+
+func (s *PipeSchema) validate(value interface{}, ctx *validationContext) ValidationResult {
+	first := validateChild(s.from, value, ctx)
+	if !first.Valid || first.Value == nil {
+		return first
+	}
+
+	second := validateChild(s.to, first.Value, ctx)
+	second.Warnings = concatErrors(first.Warnings, second.Warnings)
+	return second
+}
+
+func (s *StringSchema) Pipe(next Schema) *PipeSchema {
+	return Pipe(s, next)
+}
+
+func (s *NumberSchema) Pipe(next Schema) *PipeSchema {
+	return Pipe(s, next)
+}
+
+func (s *BigIntSchema) Pipe(next Schema) *PipeSchema {
+	return Pipe(s, next)
+}
+
+func (s *RecordSchema) Pipe(next Schema) *PipeSchema {
+	return Pipe(s, next)
+}
+
+func (s *MapSchema) Pipe(next Schema) *PipeSchema {
+	return Pipe(s, next)
+}
+
+func (s *BooleanSchema) Pipe(next Schema) *PipeSchema {
+	return Pipe(s, next)
+}
+
+func (s *DateSchema) Pipe(next Schema) *PipeSchema {
+	return Pipe(s, next)
+}
+
+func (s *ObjectSchema) Pipe(next Schema) *PipeSchema {
+	return Pipe(s, next)
+}
+
+func (s *ArraySchema) Pipe(next Schema) *PipeSchema {
+	return Pipe(s, next)
+}
+
+func (s *SetSchema) Pipe(next Schema) *PipeSchema {
+	return Pipe(s, next)
+}
+
+func (s *TupleSchema) Pipe(next Schema) *PipeSchema {
+	return Pipe(s, next)
+}
+
+func (s *UnionSchema) Pipe(next Schema) *PipeSchema {
+	return Pipe(s, next)
+}
+
+func (s *DiscriminatedUnionSchema) Pipe(next Schema) *PipeSchema {
+	return Pipe(s, next)
+}
+
+func (s *LiteralSchema) Pipe(next Schema) *PipeSchema {
+	return Pipe(s, next)
+}
+
+func (s *EnumSchema) Pipe(next Schema) *PipeSchema {
+	return Pipe(s, next)
+}
+
+func (s *NativeEnumSchema[T]) Pipe(next Schema) *PipeSchema {
+	return Pipe(s, next)
+}
+
+func (s *NullableSchema) Pipe(next Schema) *PipeSchema {
+	return Pipe(s, next)
+}
+
+func (s *AnySchema) Pipe(next Schema) *PipeSchema {
+	return Pipe(s, next)
+}
+
+func (s *UnknownSchema) Pipe(next Schema) *PipeSchema {
+	return Pipe(s, next)
+}
+
+func (s *OrderedSchema) Pipe(next Schema) *PipeSchema {
+	return Pipe(s, next)
+}
+
+func (s *CustomSchema) Pipe(next Schema) *PipeSchema {
+	return Pipe(s, next)
+}
+
+func (s *TransformSchema) Pipe(next Schema) *PipeSchema {
+	return Pipe(s, next)
+}
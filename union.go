@@ -3,11 +3,15 @@ package god
 import (
 	"fmt"
 	"reflect"
+	"strings"
 )
 
 type UnionSchema struct {
 	BaseSchema
 	schemas []Schema
+	// exclusive rejects values matching more than one option, as JSON
+	// Schema's oneOf does. It is set by FromJSONSchema.
+	exclusive bool
 }
 
 func Union(schemas ...Schema) *UnionSchema {
@@ -32,40 +36,109 @@ func (s *UnionSchema) Default(value interface{}) Schema {
 	return s
 }
 
+func (s *UnionSchema) DefaultFunc(fn func() interface{}) Schema {
+	s.BaseSchema.setDefaultFunc(fn)
+	return s
+}
+
+func (s *UnionSchema) Readonly() *UnionSchema {
+	s.BaseSchema.setReadonly()
+	return s
+}
+
+func (s *UnionSchema) WithMessage(code, message string) *UnionSchema {
+	s.BaseSchema.setMessage(code, message)
+	return s
+}
+
 func (s *UnionSchema) Validate(value interface{}) ValidationResult {
+	return s.validate(value, newContext())
+}
+
+func (s *UnionSchema) validate(value interface{}, ctx *validationContext) ValidationResult {
 	processedValue, shouldReturn, result := s.handleNil(value)
 	if shouldReturn {
 		return result
 	}
 
-	var allErrors []ValidationError
+	allErrors := make([][]ValidationError, len(s.schemas))
+	closest := -1
+	matched := -1
+	var match ValidationResult
 
 	for i, schema := range s.schemas {
-		result := schema.Validate(processedValue)
-		if result.Valid {
+		result := validateChild(schema, processedValue, ctx)
+		if result.Valid && !s.exclusive {
 			return result
 		}
-		
-		for _, err := range result.Errors {
-			err.Field = fmt.Sprintf("union[%d]", i)
-			allErrors = append(allErrors, err)
+		if result.Valid && matched >= 0 {
+			return ValidationResult{
+				Valid: false,
+				Errors: s.applyMessages([]ValidationError{{
+					Message: fmt.Sprintf("value matches more than one of the union types (union[%d] and union[%d])", matched, i),
+					Code:    "invalid_union",
+					Value:   value,
+					Params:  map[string]interface{}{"alternatives": len(s.schemas), "matches": []int{matched, i}},
+				}}),
+			}
+		}
+		if result.Valid {
+			matched, match = i, result
+			continue
 		}
+
+		allErrors[i] = result.Errors
+		if closest == -1 || closerUnionMatch(result.Errors, allErrors[closest]) {
+			closest = i
+		}
+	}
+
+	if matched >= 0 {
+		return match
+	}
+
+	message := fmt.Sprintf("value does not match any of the union types (%d alternatives tried)", len(s.schemas))
+	if closest >= 0 && len(allErrors[closest]) > 0 {
+		message += fmt.Sprintf("; closest match union[%d]: %s", closest, allErrors[closest][0].Error())
 	}
 
 	return ValidationResult{
 		Valid: false,
-		Errors: []ValidationError{{
-			Message: fmt.Sprintf("value does not match any of the union types (%d alternatives tried)", len(s.schemas)),
-			Code:    "invalid_union",
-			Value:   value,
-		}},
+		Errors: s.applyMessages([]ValidationError{{
+			Message:     message,
+			Code:        "invalid_union",
+			Value:       value,
+			Params:      map[string]interface{}{"alternatives": len(s.schemas), "closest": closest},
+			UnionErrors: allErrors,
+		}}),
 	}
 }
 
+// closerUnionMatch reports whether an alternative that failed with errors a
+// got further than one that failed with errors b. A type mismatch on the value
+// itself is the worst outcome; otherwise fewer errors wins.
+func closerUnionMatch(a, b []ValidationError) bool {
+	aMismatch, bMismatch := isRootTypeMismatch(a), isRootTypeMismatch(b)
+	if aMismatch != bMismatch {
+		return bMismatch
+	}
+	return len(a) < len(b)
+}
+
+func isRootTypeMismatch(errors []ValidationError) bool {
+	for _, err := range errors {
+		if err.Code == "invalid_type" && len(err.Path) == 0 && err.Field == "" {
+			return true
+		}
+	}
+	return false
+}
+
 type DiscriminatedUnionSchema struct {
 	BaseSchema
-	discriminant string
-	options      map[string]Schema
+	discriminant  string
+	options       map[string]Schema
+	matchByString bool
 }
 
 func DiscriminatedUnion(discriminant string, options map[string]Schema) *DiscriminatedUnionSchema {
@@ -76,6 +149,87 @@ func DiscriminatedUnion(discriminant string, options map[string]Schema) *Discrim
 	}
 }
 
+func MustDiscriminatedUnion(discriminant string, options map[string]Schema) *DiscriminatedUnionSchema {
+	schema := DiscriminatedUnion(discriminant, options)
+	if err := schema.Check(); err != nil {
+		panic(err)
+	}
+	return schema
+}
+
+func (s *DiscriminatedUnionSchema) Check() error {
+	keys := sortedKeys(s.options)
+
+	var problems []string
+	for _, key := range keys {
+		literal, problem := s.discriminantLiteral(s.options[key])
+		if problem != "" {
+			problems = append(problems, fmt.Sprintf("option '%s' %s", key, problem))
+			continue
+		}
+
+		if fmt.Sprintf("%v", literal.value) != key {
+			problems = append(problems, fmt.Sprintf("option '%s' field '%s' has literal value '%v'", key, s.discriminant, literal.value))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("inconsistent discriminated union: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// MatchByString restores the lenient lookup that compares the discriminant's
+// string form against the option keys, so 1 and "1" select the same option.
+func (s *DiscriminatedUnionSchema) MatchByString() *DiscriminatedUnionSchema {
+	s.matchByString = true
+	return s
+}
+
+// discriminantLiteral follows the dotted discriminant path through nested
+// object schemas and returns the literal the option declares there.
+func (s *DiscriminatedUnionSchema) discriminantLiteral(option Schema) (*LiteralSchema, string) {
+	current := option
+	for _, segment := range strings.Split(s.discriminant, ".") {
+		object, ok := current.(*ObjectSchema)
+		if !ok {
+			if current == option {
+				return nil, "is not an object schema"
+			}
+			return nil, fmt.Sprintf("has no object schema on the path to '%s'", s.discriminant)
+		}
+		field, exists := object.getEffectiveFields()[segment]
+		if !exists {
+			return nil, fmt.Sprintf("has no '%s' field", s.discriminant)
+		}
+		current = field
+	}
+
+	literal, ok := current.(*LiteralSchema)
+	if !ok {
+		return nil, fmt.Sprintf("field '%s' is not a literal", s.discriminant)
+	}
+	return literal, ""
+}
+
+func (s *DiscriminatedUnionSchema) selectOption(discriminantValue interface{}) (Schema, bool) {
+	for _, key := range sortedKeys(s.options) {
+		if literal, problem := s.discriminantLiteral(s.options[key]); problem == "" {
+			if literalEqual(literal.value, discriminantValue) {
+				return s.options[key], true
+			}
+		} else if str, ok := discriminantValue.(string); ok && str == key {
+			return s.options[key], true
+		}
+	}
+
+	if s.matchByString {
+		schema, exists := s.options[fmt.Sprintf("%v", discriminantValue)]
+		return schema, exists
+	}
+	return nil, false
+}
+
 func (s *DiscriminatedUnionSchema) Optional() Schema {
 	s.BaseSchema.setOptional()
 	return s
@@ -91,66 +245,78 @@ func (s *DiscriminatedUnionSchema) Default(value interface{}) Schema {
 	return s
 }
 
+func (s *DiscriminatedUnionSchema) DefaultFunc(fn func() interface{}) Schema {
+	s.BaseSchema.setDefaultFunc(fn)
+	return s
+}
+
+func (s *DiscriminatedUnionSchema) Readonly() *DiscriminatedUnionSchema {
+	s.BaseSchema.setReadonly()
+	return s
+}
+
+func (s *DiscriminatedUnionSchema) WithMessage(code, message string) *DiscriminatedUnionSchema {
+	s.BaseSchema.setMessage(code, message)
+	return s
+}
+
 func (s *DiscriminatedUnionSchema) Validate(value interface{}) ValidationResult {
+	return s.validate(value, newContext())
+}
+
+func (s *DiscriminatedUnionSchema) validate(value interface{}, ctx *validationContext) ValidationResult {
 	processedValue, shouldReturn, result := s.handleNil(value)
 	if shouldReturn {
 		return result
 	}
 
-	// Must be an object
-	v := reflect.ValueOf(processedValue)
-	if v.Kind() == reflect.Ptr {
-		v = v.Elem()
+	objMap, ok := toObjectMap(processedValue, ctx.structTag)
+	if !ok {
+		return ValidationResult{
+			Valid:  false,
+			Errors: s.applyMessages([]ValidationError{{Message: "expected object for discriminated union", Code: "invalid_type", Value: value, Params: map[string]interface{}{"expected": "object"}}}),
+		}
 	}
 
-	var objMap map[string]interface{}
-	var ok bool
-
-	switch v.Kind() {
-	case reflect.Map:
-		objMap, ok = convertMapToStringInterface(processedValue)
-		if !ok {
-			return ValidationResult{
-				Valid:  false,
-				Errors: []ValidationError{{Message: "expected object for discriminated union", Code: "invalid_type", Value: value}},
+	// Check for discriminant field, following dotted paths into nested objects
+	var discriminantValue interface{}
+	exists := false
+	for i, segment := range strings.Split(s.discriminant, ".") {
+		if i > 0 {
+			if objMap, ok = toObjectMap(discriminantValue, ctx.structTag); !ok {
+				exists = false
+				break
 			}
 		}
-	case reflect.Struct:
-		objMap = structToMap(v)
-	default:
-		return ValidationResult{
-			Valid:  false,
-			Errors: []ValidationError{{Message: "expected object for discriminated union", Code: "invalid_type", Value: value}},
+		discriminantValue, exists = objMap[segment]
+		if !exists {
+			break
 		}
 	}
-
-	// Check for discriminant field
-	discriminantValue, exists := objMap[s.discriminant]
 	if !exists {
 		return ValidationResult{
 			Valid: false,
-			Errors: []ValidationError{{
+			Errors: s.applyMessages([]ValidationError{{
 				Message: fmt.Sprintf("missing discriminant field '%s'", s.discriminant),
 				Code:    "invalid_union",
 				Value:   value,
-			}},
+			}}),
 		}
 	}
 
-	discriminantStr := fmt.Sprintf("%v", discriminantValue)
-	schema, exists := s.options[discriminantStr]
+	schema, exists := s.selectOption(discriminantValue)
 	if !exists {
 		return ValidationResult{
 			Valid: false,
-			Errors: []ValidationError{{
-				Message: fmt.Sprintf("unknown discriminant value '%s'", discriminantStr),
+			Errors: s.applyMessages([]ValidationError{{
+				Message: fmt.Sprintf("unknown discriminant value '%v'", discriminantValue),
 				Code:    "invalid_union",
 				Value:   discriminantValue,
-			}},
+			}}),
 		}
 	}
 
-	return schema.Validate(processedValue)
+	return validateChild(schema, processedValue, ctx)
 }
 
 type LiteralSchema struct {
@@ -180,29 +346,69 @@ func (s *LiteralSchema) Default(value interface{}) Schema {
 	return s
 }
 
+func (s *LiteralSchema) DefaultFunc(fn func() interface{}) Schema {
+	s.BaseSchema.setDefaultFunc(fn)
+	return s
+}
+
+func (s *LiteralSchema) Readonly() *LiteralSchema {
+	s.BaseSchema.setReadonly()
+	return s
+}
+
+func (s *LiteralSchema) WithMessage(code, message string) *LiteralSchema {
+	s.BaseSchema.setMessage(code, message)
+	return s
+}
+
 func (s *LiteralSchema) Validate(value interface{}) ValidationResult {
 	processedValue, shouldReturn, result := s.handleNil(value)
 	if shouldReturn {
 		return result
 	}
 
-	if !reflect.DeepEqual(processedValue, s.value) {
+	if !literalEqual(processedValue, s.value) {
 		return ValidationResult{
 			Valid: false,
-			Errors: []ValidationError{{
+			Errors: s.applyMessages([]ValidationError{{
 				Message: fmt.Sprintf("expected literal value %v", s.value),
 				Code:    "invalid_literal",
 				Value:   value,
-			}},
+				Params:  map[string]interface{}{"expected": s.value},
+			}}),
 		}
 	}
 
 	return ValidationResult{Valid: true, Value: processedValue}
 }
 
+// literalEqual compares numbers by value, so Literal(1) matches the float64
+// that encoding/json decodes 1 into. Numbers never equal strings or booleans,
+// and other values must be deeply equal.
+func literalEqual(a, b interface{}) bool {
+	if x, ok := exactInteger(a); ok {
+		if y, ok := exactInteger(b); ok {
+			return x == y
+		}
+	}
+	x, aNumber := convertToFloat64(a)
+	y, bNumber := convertToFloat64(b)
+	if aNumber && bNumber {
+		return x == y
+	}
+	return reflect.DeepEqual(a, b)
+}
+
 type EnumSchema struct {
 	BaseSchema
-	values []interface{}
+	values          []interface{}
+	aliases         []enumAlias
+	caseInsensitive bool
+}
+
+type enumAlias struct {
+	alias     interface{}
+	canonical interface{}
 }
 
 func Enum(values ...interface{}) *EnumSchema {
@@ -212,6 +418,29 @@ func Enum(values ...interface{}) *EnumSchema {
 	}
 }
 
+func (s *EnumSchema) Alias(canonical interface{}, aliases ...interface{}) *EnumSchema {
+	for _, alias := range aliases {
+		s.aliases = append(s.aliases, enumAlias{alias: alias, canonical: canonical})
+	}
+	return s
+}
+
+func (s *EnumSchema) CaseInsensitive() *EnumSchema {
+	s.caseInsensitive = true
+	return s
+}
+
+func (s *EnumSchema) matches(value, member interface{}) bool {
+	if s.caseInsensitive {
+		str, ok := value.(string)
+		memberStr, memberOk := member.(string)
+		if ok && memberOk {
+			return strings.EqualFold(str, memberStr)
+		}
+	}
+	return reflect.DeepEqual(value, member)
+}
+
 func (s *EnumSchema) Optional() Schema {
 	s.BaseSchema.setOptional()
 	return s
@@ -227,6 +456,21 @@ func (s *EnumSchema) Default(value interface{}) Schema {
 	return s
 }
 
+func (s *EnumSchema) DefaultFunc(fn func() interface{}) Schema {
+	s.BaseSchema.setDefaultFunc(fn)
+	return s
+}
+
+func (s *EnumSchema) Readonly() *EnumSchema {
+	s.BaseSchema.setReadonly()
+	return s
+}
+
+func (s *EnumSchema) WithMessage(code, message string) *EnumSchema {
+	s.BaseSchema.setMessage(code, message)
+	return s
+}
+
 func (s *EnumSchema) Validate(value interface{}) ValidationResult {
 	processedValue, shouldReturn, result := s.handleNil(value)
 	if shouldReturn {
@@ -234,19 +478,132 @@ func (s *EnumSchema) Validate(value interface{}) ValidationResult {
 	}
 
 	for _, enumValue := range s.values {
-		if reflect.DeepEqual(processedValue, enumValue) {
-			return ValidationResult{Valid: true, Value: processedValue}
+		if s.matches(processedValue, enumValue) {
+			return ValidationResult{Valid: true, Value: enumValue}
+		}
+	}
+
+	for _, alias := range s.aliases {
+		if s.matches(processedValue, alias.alias) {
+			return ValidationResult{Valid: true, Value: alias.canonical}
+		}
+	}
+
+	return ValidationResult{
+		Valid: false,
+		Errors: s.applyMessages([]ValidationError{{
+			Message: fmt.Sprintf("expected one of %v", s.values),
+			Code:    "invalid_enum_value",
+			Value:   value,
+			Params:  map[string]interface{}{"options": s.values},
+		}}),
+	}
+}
+
+// NativeEnumSchema validates membership in a set of typed Go constants and
+// returns the matching constant, so result.Value keeps the concrete type T.
+type NativeEnumSchema[T comparable] struct {
+	BaseSchema
+	values []T
+}
+
+func NativeEnum[T comparable](values ...T) *NativeEnumSchema[T] {
+	return &NativeEnumSchema[T]{
+		BaseSchema: BaseSchema{isRequired: true},
+		values:     values,
+	}
+}
+
+func (s *NativeEnumSchema[T]) Values() []T {
+	return append([]T(nil), s.values...)
+}
+
+func (s *NativeEnumSchema[T]) Optional() Schema {
+	s.BaseSchema.setOptional()
+	return s
+}
+
+func (s *NativeEnumSchema[T]) Required() Schema {
+	s.BaseSchema.setRequired()
+	return s
+}
+
+func (s *NativeEnumSchema[T]) Default(value interface{}) Schema {
+	s.BaseSchema.setDefault(value)
+	return s
+}
+
+func (s *NativeEnumSchema[T]) DefaultFunc(fn func() interface{}) Schema {
+	s.BaseSchema.setDefaultFunc(fn)
+	return s
+}
+
+func (s *NativeEnumSchema[T]) Readonly() *NativeEnumSchema[T] {
+	s.BaseSchema.setReadonly()
+	return s
+}
+
+func (s *NativeEnumSchema[T]) WithMessage(code, message string) *NativeEnumSchema[T] {
+	s.BaseSchema.setMessage(code, message)
+	return s
+}
+
+func (s *NativeEnumSchema[T]) Validate(value interface{}) ValidationResult {
+	processedValue, shouldReturn, result := s.handleNil(value)
+	if shouldReturn {
+		return result
+	}
+
+	if typed, ok := convertToNative[T](processedValue); ok {
+		for _, enumValue := range s.values {
+			if typed == enumValue {
+				return ValidationResult{Valid: true, Value: enumValue}
+			}
 		}
 	}
 
 	return ValidationResult{
 		Valid: false,
-		Errors: []ValidationError{{
+		Errors: s.applyMessages([]ValidationError{{
 			Message: fmt.Sprintf("expected one of %v", s.values),
 			Code:    "invalid_enum_value",
 			Value:   value,
-		}},
+			Params:  map[string]interface{}{"options": s.values},
+		}}),
+	}
+}
+
+// convertToNative accepts values of type T as well as values of its
+// underlying kind, such as a plain string for a `type Role string`. Numbers
+// only convert when no precision is lost, so 2.0 matches a constant of 2.
+func convertToNative[T comparable](value interface{}) (T, bool) {
+	var zero T
+	if typed, ok := value.(T); ok {
+		return typed, true
+	}
+
+	target := reflect.TypeOf(zero)
+	v := reflect.ValueOf(value)
+	if target == nil || !v.IsValid() || !v.Type().ConvertibleTo(target) {
+		return zero, false
+	}
+
+	if v.Kind() != target.Kind() {
+		num, ok := convertToFloat64(value)
+		if !ok {
+			return zero, false
+		}
+		if _, targetNumeric := convertToFloat64(reflect.Zero(target).Interface()); !targetNumeric {
+			return zero, false
+		}
+		converted := v.Convert(target)
+		if back, _ := convertToFloat64(converted.Interface()); back != num {
+			return zero, false
+		}
+		return converted.Interface().(T), true
 	}
+
+	return v.Convert(target).Interface().(T), true
 }
 
 type NullableSchema struct {
@@ -261,6 +618,14 @@ func Nullable(schema Schema) *NullableSchema {
 	}
 }
 
+// Nullish accepts null and, as an object field, a missing key. It is
+// Nullable(schema).Optional().
+func Nullish(schema Schema) *NullableSchema {
+	s := Nullable(schema)
+	s.BaseSchema.setOptional()
+	return s
+}
+
 func (s *NullableSchema) Optional() Schema {
 	s.BaseSchema.setOptional()
 	return s
@@ -276,10 +641,24 @@ func (s *NullableSchema) Default(value interface{}) Schema {
 	return s
 }
 
+func (s *NullableSchema) DefaultFunc(fn func() interface{}) Schema {
+	s.BaseSchema.setDefaultFunc(fn)
+	return s
+}
+
+func (s *NullableSchema) Readonly() *NullableSchema {
+	s.BaseSchema.setReadonly()
+	return s
+}
+
 func (s *NullableSchema) Validate(value interface{}) ValidationResult {
-	if value == nil {
+	return s.validate(value, newContext())
+}
+
+func (s *NullableSchema) validate(value interface{}, ctx *validationContext) ValidationResult {
+	if isNil(value) {
 		return ValidationResult{Valid: true, Value: nil}
 	}
 
-	return s.schema.Validate(value)
-}
\ No newline at end of file
+	return validateChild(s.schema, value, ctx)
+}
@@ -2,6 +2,10 @@ package god
 
 import (
 	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -30,6 +34,21 @@ func (s *AnySchema) Default(value interface{}) Schema {
 	return s
 }
 
+func (s *AnySchema) DefaultFunc(fn func() interface{}) Schema {
+	s.BaseSchema.setDefaultFunc(fn)
+	return s
+}
+
+func (s *AnySchema) Readonly() *AnySchema {
+	s.BaseSchema.setReadonly()
+	return s
+}
+
+func (s *AnySchema) WithMessage(code, message string) *AnySchema {
+	s.BaseSchema.setMessage(code, message)
+	return s
+}
+
 func (s *AnySchema) Validate(value interface{}) ValidationResult {
 	processedValue, shouldReturn, result := s.handleNil(value)
 	if shouldReturn {
@@ -64,6 +83,21 @@ func (s *UnknownSchema) Default(value interface{}) Schema {
 	return s
 }
 
+func (s *UnknownSchema) DefaultFunc(fn func() interface{}) Schema {
+	s.BaseSchema.setDefaultFunc(fn)
+	return s
+}
+
+func (s *UnknownSchema) Readonly() *UnknownSchema {
+	s.BaseSchema.setReadonly()
+	return s
+}
+
+func (s *UnknownSchema) WithMessage(code, message string) *UnknownSchema {
+	s.BaseSchema.setMessage(code, message)
+	return s
+}
+
 func (s *UnknownSchema) Validate(value interface{}) ValidationResult {
 	processedValue, shouldReturn, result := s.handleNil(value)
 	if shouldReturn {
@@ -98,6 +132,21 @@ func (s *VoidSchema) Default(value interface{}) Schema {
 	return s
 }
 
+func (s *VoidSchema) DefaultFunc(fn func() interface{}) Schema {
+	s.BaseSchema.setDefaultFunc(fn)
+	return s
+}
+
+func (s *VoidSchema) Readonly() *VoidSchema {
+	s.BaseSchema.setReadonly()
+	return s
+}
+
+func (s *VoidSchema) WithMessage(code, message string) *VoidSchema {
+	s.BaseSchema.setMessage(code, message)
+	return s
+}
+
 func (s *VoidSchema) Validate(value interface{}) ValidationResult {
 	_, shouldReturn, result := s.handleNil(value)
 	if shouldReturn {
@@ -132,21 +181,67 @@ func (s *NeverSchema) Default(value interface{}) Schema {
 	return s
 }
 
+func (s *NeverSchema) DefaultFunc(fn func() interface{}) Schema {
+	s.BaseSchema.setDefaultFunc(fn)
+	return s
+}
+
+func (s *NeverSchema) Readonly() *NeverSchema {
+	s.BaseSchema.setReadonly()
+	return s
+}
+
+func (s *NeverSchema) WithMessage(code, message string) *NeverSchema {
+	s.BaseSchema.setMessage(code, message)
+	return s
+}
+
 func (s *NeverSchema) Validate(value interface{}) ValidationResult {
 	return ValidationResult{
 		Valid: false,
-		Errors: []ValidationError{{
+		Errors: s.applyMessages([]ValidationError{{
 			Message: "never type should never be used",
 			Code:    "invalid_type",
 			Value:   value,
-		}},
+		}}),
+	}
+}
+
+// globalClock holds the function set with SetClock, or nil for time.Now.
+var globalClock atomic.Pointer[func() time.Time]
+
+func now() time.Time {
+	if clock := globalClock.Load(); clock != nil {
+		return (*clock)()
+	}
+	return time.Now()
+}
+
+// SetClock replaces the time source used by relative date checks such as
+// Past, Future and MinAge. Passing nil restores time.Now. It is safe to call
+// while other goroutines validate.
+func SetClock(clock func() time.Time) {
+	if clock == nil {
+		globalClock.Store(nil)
+		return
 	}
+	globalClock.Store(&clock)
 }
 
+var defaultDateLayouts = []string{time.RFC3339, "2006-01-02"}
+
 type DateSchema struct {
 	BaseSchema
-	min *time.Time
-	max *time.Time
+	min       *time.Time
+	max       *time.Time
+	minAge    *int
+	maxAge    *int
+	layouts   []string
+	epochUnit time.Duration
+	location  *time.Location
+	dateOnly  bool
+	past      bool
+	future    bool
 }
 
 func Date() *DateSchema {
@@ -165,6 +260,73 @@ func (s *DateSchema) Max(date time.Time) *DateSchema {
 	return s
 }
 
+// Past requires the date to be before the current time at validation.
+func (s *DateSchema) Past() *DateSchema {
+	s.past = true
+	return s
+}
+
+// Future requires the date to be after the current time at validation.
+func (s *DateSchema) Future() *DateSchema {
+	s.future = true
+	return s
+}
+
+func (s *DateSchema) MinAge(years int) *DateSchema {
+	s.minAge = &years
+	return s
+}
+
+func (s *DateSchema) MaxAge(years int) *DateSchema {
+	s.maxAge = &years
+	return s
+}
+
+// Layouts adds time.Parse layouts that are tried, in order, after RFC3339
+// and 2006-01-02.
+func (s *DateSchema) Layouts(layouts ...string) *DateSchema {
+	s.layouts = append(s.layouts, layouts...)
+	return s
+}
+
+// Unix accepts integer seconds since the epoch.
+func (s *DateSchema) Unix() *DateSchema {
+	s.epochUnit = time.Second
+	return s
+}
+
+// UnixMilli accepts integer milliseconds since the epoch.
+func (s *DateSchema) UnixMilli() *DateSchema {
+	s.epochUnit = time.Millisecond
+	return s
+}
+
+// InLocation interprets layouts without a zone, such as 2006-01-02, in loc
+// and converts every accepted date to loc before comparing and returning it.
+func (s *DateSchema) InLocation(loc *time.Location) *DateSchema {
+	s.location = loc
+	return s
+}
+
+// DateOnly truncates dates and the Min/Max bounds to midnight before
+// comparing them, so Min(time.Now()) means "today or later". Bounds are
+// truncated in the date's location (see InLocation).
+func (s *DateSchema) DateOnly() *DateSchema {
+	s.dateOnly = true
+	return s
+}
+
+func (s *DateSchema) normalize(date time.Time, loc *time.Location) time.Time {
+	if loc != nil {
+		date = date.In(loc)
+	}
+	if s.dateOnly {
+		year, month, day := date.Date()
+		date = time.Date(year, month, day, 0, 0, 0, 0, date.Location())
+	}
+	return date
+}
+
 func (s *DateSchema) Optional() Schema {
 	s.BaseSchema.setOptional()
 	return s
@@ -180,12 +342,33 @@ func (s *DateSchema) Default(value interface{}) Schema {
 	return s
 }
 
+func (s *DateSchema) DefaultFunc(fn func() interface{}) Schema {
+	s.BaseSchema.setDefaultFunc(fn)
+	return s
+}
+
+func (s *DateSchema) Readonly() *DateSchema {
+	s.BaseSchema.setReadonly()
+	return s
+}
+
+func (s *DateSchema) WithMessage(code, message string) *DateSchema {
+	s.BaseSchema.setMessage(code, message)
+	return s
+}
+
 func (s *DateSchema) Validate(value interface{}) ValidationResult {
+	return s.validate(value, newContext())
+}
+
+func (s *DateSchema) validate(value interface{}, ctx *validationContext) ValidationResult {
 	processedValue, shouldReturn, result := s.handleNil(value)
 	if shouldReturn {
 		return result
 	}
 
+	layouts := append(append([]string{}, defaultDateLayouts...), s.layouts...)
+
 	var date time.Time
 	var ok bool
 
@@ -194,51 +377,244 @@ func (s *DateSchema) Validate(value interface{}) ValidationResult {
 		date = v
 		ok = true
 	case string:
-		if parsed, err := time.Parse(time.RFC3339, v); err == nil {
-			date = parsed
-			ok = true
-		} else if parsed, err := time.Parse("2006-01-02", v); err == nil {
-			date = parsed
-			ok = true
+		loc := time.UTC
+		if s.location != nil {
+			loc = s.location
+		}
+		for _, layout := range layouts {
+			if parsed, err := time.ParseInLocation(layout, v, loc); err == nil {
+				date = parsed
+				ok = true
+				break
+			}
+		}
+	default:
+		if s.epochUnit != 0 {
+			date, ok = epochToTime(processedValue, s.epochUnit)
 		}
 	}
 
 	if !ok {
+		message := "expected valid date"
+		if _, isString := processedValue.(string); isString {
+			message = fmt.Sprintf("expected valid date (tried layouts: %s)", strings.Join(layouts, ", "))
+		}
 		return ValidationResult{
 			Valid: false,
-			Errors: []ValidationError{{
-				Message: "expected valid date",
+			Errors: s.applyMessages([]ValidationError{{
+				Message: message,
 				Code:    "invalid_date",
 				Value:   value,
-			}},
+				Params:  map[string]interface{}{"layouts": layouts},
+			}}),
 		}
 	}
 
+	date = s.normalize(date, s.location)
+
 	var errors []ValidationError
 
-	if s.min != nil && date.Before(*s.min) {
+	if s.min != nil && date.Before(s.normalize(*s.min, date.Location())) {
 		errors = append(errors, ValidationError{
 			Message: fmt.Sprintf("date must be after %s", s.min.Format(time.RFC3339)),
 			Code:    "too_small",
 			Value:   date,
+			Params:  map[string]interface{}{"min": *s.min},
 		})
 	}
 
-	if s.max != nil && date.After(*s.max) {
+	if s.max != nil && date.After(s.normalize(*s.max, date.Location())) {
 		errors = append(errors, ValidationError{
 			Message: fmt.Sprintf("date must be before %s", s.max.Format(time.RFC3339)),
 			Code:    "too_big",
 			Value:   date,
+			Params:  map[string]interface{}{"max": *s.max},
 		})
 	}
 
+	if s.past || s.future {
+		current := s.normalize(ctx.now(), date.Location())
+
+		if s.past && !date.Before(current) {
+			errors = append(errors, ValidationError{
+				Message: "date must be in the past",
+				Code:    "too_big",
+				Value:   date,
+				Params:  map[string]interface{}{"max": current},
+			})
+		}
+
+		if s.future && !date.After(current) {
+			errors = append(errors, ValidationError{
+				Message: "date must be in the future",
+				Code:    "too_small",
+				Value:   date,
+				Params:  map[string]interface{}{"min": current},
+			})
+		}
+	}
+
+	if s.minAge != nil || s.maxAge != nil {
+		age := ageAt(date, ctx.now())
+
+		if s.minAge != nil && age < *s.minAge {
+			errors = append(errors, ValidationError{
+				Message: fmt.Sprintf("must be at least %d years old", *s.minAge),
+				Code:    "too_small",
+				Value:   date,
+				Params:  map[string]interface{}{"min": *s.minAge},
+			})
+		}
+
+		if s.maxAge != nil && age > *s.maxAge {
+			errors = append(errors, ValidationError{
+				Message: fmt.Sprintf("must be at most %d years old", *s.maxAge),
+				Code:    "too_big",
+				Value:   date,
+				Params:  map[string]interface{}{"max": *s.maxAge},
+			})
+		}
+	}
+
 	if len(errors) > 0 {
-		return ValidationResult{Valid: false, Errors: errors}
+		return ValidationResult{Valid: false, Errors: s.applyMessages(errors)}
 	}
 
 	return ValidationResult{Valid: true, Value: date}
 }
 
+func epochToTime(value interface{}, unit time.Duration) (time.Time, bool) {
+	num, ok := convertToFloat64(value)
+	if !ok || !isInteger(num) {
+		return time.Time{}, false
+	}
+	if unit == time.Millisecond {
+		return time.UnixMilli(int64(num)).UTC(), true
+	}
+	return time.Unix(int64(num), 0).UTC(), true
+}
+
+// ageAt returns the number of whole years between birth and at. Someone born
+// on February 29 turns a year older on March 1 in non-leap years.
+func ageAt(birth, at time.Time) int {
+	at = at.In(birth.Location())
+	age := at.Year() - birth.Year()
+	if at.Month() < birth.Month() || (at.Month() == birth.Month() && at.Day() < birth.Day()) {
+		age--
+	}
+	return age
+}
+
+type OrderedSchema struct {
+	BaseSchema
+	compare func(a, b interface{}) int
+	min     interface{}
+	max     interface{}
+	hasMin  bool
+	hasMax  bool
+}
+
+func Ordered(compare func(a, b interface{}) int) *OrderedSchema {
+	return &OrderedSchema{
+		BaseSchema: BaseSchema{isRequired: true},
+		compare:    compare,
+	}
+}
+
+func (s *OrderedSchema) Min(value interface{}) *OrderedSchema {
+	s.min = value
+	s.hasMin = true
+	return s
+}
+
+func (s *OrderedSchema) Max(value interface{}) *OrderedSchema {
+	s.max = value
+	s.hasMax = true
+	return s
+}
+
+func (s *OrderedSchema) Optional() Schema {
+	s.BaseSchema.setOptional()
+	return s
+}
+
+func (s *OrderedSchema) Required() Schema {
+	s.BaseSchema.setRequired()
+	return s
+}
+
+func (s *OrderedSchema) Default(value interface{}) Schema {
+	s.BaseSchema.setDefault(value)
+	return s
+}
+
+func (s *OrderedSchema) DefaultFunc(fn func() interface{}) Schema {
+	s.BaseSchema.setDefaultFunc(fn)
+	return s
+}
+
+func (s *OrderedSchema) Readonly() *OrderedSchema {
+	s.BaseSchema.setReadonly()
+	return s
+}
+
+func (s *OrderedSchema) WithMessage(code, message string) *OrderedSchema {
+	s.BaseSchema.setMessage(code, message)
+	return s
+}
+
+func (s *OrderedSchema) boundType() reflect.Type {
+	if s.hasMin {
+		return reflect.TypeOf(s.min)
+	}
+	if s.hasMax {
+		return reflect.TypeOf(s.max)
+	}
+	return nil
+}
+
+func (s *OrderedSchema) Validate(value interface{}) ValidationResult {
+	processedValue, shouldReturn, result := s.handleNil(value)
+	if shouldReturn {
+		return result
+	}
+
+	// The comparator is only given values of the bounds' type, so one that
+	// uses a type assertion cannot panic on other input.
+	if expected := s.boundType(); expected != nil && reflect.TypeOf(processedValue) != expected {
+		return ValidationResult{
+			Valid:  false,
+			Errors: s.applyMessages([]ValidationError{{Message: fmt.Sprintf("expected %v", expected), Code: "invalid_type", Value: value, Params: map[string]interface{}{"expected": expected.String()}}}),
+		}
+	}
+
+	var errors []ValidationError
+
+	if s.hasMin && s.compare(processedValue, s.min) < 0 {
+		errors = append(errors, ValidationError{
+			Message: fmt.Sprintf("value must be greater than or equal to %v", s.min),
+			Code:    "too_small",
+			Value:   processedValue,
+			Params:  map[string]interface{}{"min": s.min},
+		})
+	}
+
+	if s.hasMax && s.compare(processedValue, s.max) > 0 {
+		errors = append(errors, ValidationError{
+			Message: fmt.Sprintf("value must be less than or equal to %v", s.max),
+			Code:    "too_big",
+			Value:   processedValue,
+			Params:  map[string]interface{}{"max": s.max},
+		})
+	}
+
+	if len(errors) > 0 {
+		return ValidationResult{Valid: false, Errors: s.applyMessages(errors)}
+	}
+
+	return ValidationResult{Valid: true, Value: processedValue}
+}
+
 func Lazy(schemaFn func() Schema) Schema {
 	return &LazySchema{
 		BaseSchema: BaseSchema{isRequired: true},
@@ -249,12 +625,26 @@ func Lazy(schemaFn func() Schema) Schema {
 type LazySchema struct {
 	BaseSchema
 	schemaFn func() Schema
+	once     sync.Once
 	cached   Schema
+	selfRef  bool
 }
 
+// getSchema calls schemaFn once, even when the first validations run
+// concurrently, and reuses its result. schemaFn must not validate with the
+// schema it is building. If schemaFn returns this same Lazy schema, which
+// would otherwise recurse forever, selfRef is set and nil is returned.
 func (s *LazySchema) getSchema() Schema {
-	if s.cached == nil {
-		s.cached = s.schemaFn()
+	s.once.Do(func() {
+		schema := s.schemaFn()
+		if schema == Schema(s) {
+			s.selfRef = true
+			return
+		}
+		s.cached = schema
+	})
+	if s.selfRef {
+		return nil
 	}
 	return s.cached
 }
@@ -274,11 +664,41 @@ func (s *LazySchema) Default(value interface{}) Schema {
 	return s
 }
 
+func (s *LazySchema) DefaultFunc(fn func() interface{}) Schema {
+	s.BaseSchema.setDefaultFunc(fn)
+	return s
+}
+
+func (s *LazySchema) Readonly() *LazySchema {
+	s.BaseSchema.setReadonly()
+	return s
+}
+
+func (s *LazySchema) WithMessage(code, message string) *LazySchema {
+	s.BaseSchema.setMessage(code, message)
+	return s
+}
+
 func (s *LazySchema) Validate(value interface{}) ValidationResult {
+	return s.validate(value, newContext())
+}
+
+func (s *LazySchema) validate(value interface{}, ctx *validationContext) ValidationResult {
 	_, shouldReturn, result := s.handleNil(value)
 	if shouldReturn {
 		return result
 	}
 
-	return s.getSchema().Validate(value)
-}
\ No newline at end of file
+	schema := s.getSchema()
+	if s.selfRef {
+		return ValidationResult{
+			Valid: false,
+			Errors: s.applyMessages([]ValidationError{{
+				Message: "Lazy schema resolves to itself",
+				Code:    "invalid_schema",
+				Value:   value,
+			}}),
+		}
+	}
+	return validateChild(schema, value, ctx)
+}